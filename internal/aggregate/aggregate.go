@@ -0,0 +1,285 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/spf13/cast"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+// Function is the aggregate function applied to the readings in a window.
+type Function string
+
+const (
+	Min  Function = "min"
+	Max  Function = "max"
+	Avg  Function = "avg"
+	Last Function = "last"
+)
+
+// TagKey is the tag of the summarised readings, whose value is the aggregate function.
+const TagKey = "aggregate"
+
+// DefaultFunctions are applied if no functions are specified for the window.
+var DefaultFunctions = []Function{Min, Max, Avg, Last}
+
+// Rule is the aggregation of a device resource, which is parsed from the attributes of the resource.
+type Rule struct {
+	// Window is the length of the tumbling window, the readings are summarised once it ends.
+	Window time.Duration
+	// Functions are the aggregate functions applied to the readings in the window, each of which produces a
+	// summarised reading.
+	Functions []Function
+}
+
+// ParseRule parses the rule from the attributes 'aggregateWindow' and 'aggregateFunctions', the latter can be
+// either a list or a comma-separated string. The readings are not aggregated if no valid window is specified.
+func ParseRule(attributes map[string]interface{}) (Rule, bool, error) {
+	if attributes == nil || attributes[contracts.AggregateWindowKey] == nil {
+		return Rule{}, false, nil
+	}
+	window, err := utils.CastDuration(attributes[contracts.AggregateWindowKey])
+	if err != nil {
+		return Rule{}, false, err
+	}
+	if window <= 0 {
+		return Rule{}, false, fmt.Errorf("invalid window %v", window)
+	}
+
+	rule := Rule{Window: window}
+	var names []string
+	switch functions := attributes[contracts.AggregateFunctionsKey].(type) {
+	case nil:
+	case string:
+		names = strings.Split(functions, ",")
+	default:
+		if names, err = cast.ToStringSliceE(functions); err != nil {
+			return Rule{}, false, fmt.Errorf("invalid functions '%v'", functions)
+		}
+	}
+	for _, name := range names {
+		switch f := Function(strings.TrimSpace(name)); f {
+		case Min, Max, Avg, Last:
+			rule.Functions = append(rule.Functions, f)
+		case "":
+		default:
+			return Rule{}, false, fmt.Errorf("unknown function '%s'", f)
+		}
+	}
+	if len(rule.Functions) == 0 {
+		rule.Functions = DefaultFunctions
+	}
+	return rule, true, nil
+}
+
+// Aggregator summarises the readings of each device resource in tumbling windows.
+type Aggregator struct {
+	windows map[key]*window
+	mutex   sync.Mutex
+}
+
+type key struct {
+	deviceName   string
+	resourceName string
+}
+
+type window struct {
+	rule       Rule
+	sourceName string
+	end        time.Time
+	count      int
+	numeric    int
+	sum        float64
+	min, max   *models.CommandValue
+	minV, maxV float64
+	last       *models.CommandValue
+}
+
+func New() *Aggregator {
+	return &Aggregator{
+		windows: make(map[key]*window),
+		mutex:   sync.Mutex{},
+	}
+}
+
+// Add puts the reading into the window of its resource, a new window is started at now if there is none.
+// It returns whether a new window is started, so that the caller can schedule the flush.
+func (a *Aggregator) Add(deviceName, sourceName string, cv *models.CommandValue, rule Rule, now time.Time) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	k := key{deviceName: deviceName, resourceName: cv.DeviceResourceName}
+	w, ok := a.windows[k]
+	started := !ok
+	if !ok {
+		w = &window{rule: rule, sourceName: sourceName, end: now.Add(rule.Window)}
+		a.windows[k] = w
+	}
+	w.add(cv)
+	return started
+}
+
+// Next returns the end of the earliest window, false is returned if there is no window.
+func (a *Aggregator) Next() (time.Time, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	var next time.Time
+	for _, w := range a.windows {
+		if next.IsZero() || w.end.Before(next) {
+			next = w.end
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// Flush summarises the windows ended before now. The summarised readings of the same device, source and function
+// are returned in one AsyncValues, so that the tag of the event is consistent with its readings.
+func (a *Aggregator) Flush(now time.Time) []*contracts.AsyncValues {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	type group struct {
+		deviceName string
+		sourceName string
+		function   Function
+	}
+	groups := make(map[group]*contracts.AsyncValues)
+	for k, w := range a.windows {
+		if w.end.After(now) {
+			continue
+		}
+		delete(a.windows, k)
+		for _, f := range w.rule.Functions {
+			cv := w.summarise(f)
+			if cv == nil {
+				continue
+			}
+			g := group{deviceName: k.deviceName, sourceName: w.sourceName, function: f}
+			if groups[g] == nil {
+				groups[g] = &contracts.AsyncValues{DeviceName: k.deviceName, SourceName: w.sourceName}
+			}
+			groups[g].CommandValues = append(groups[g].CommandValues, cv)
+		}
+	}
+
+	results := make([]*contracts.AsyncValues, 0, len(groups))
+	for _, values := range groups {
+		sort.Slice(values.CommandValues, func(i, j int) bool {
+			return values.CommandValues[i].DeviceResourceName < values.CommandValues[j].DeviceResourceName
+		})
+		results = append(results, values)
+	}
+	return results
+}
+
+// Purge drops the windows of the device without summarising them.
+func (a *Aggregator) Purge(deviceName string) {
+	if a == nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for k := range a.windows {
+		if k.deviceName == deviceName {
+			delete(a.windows, k)
+		}
+	}
+}
+
+func (w *window) add(cv *models.CommandValue) {
+	w.count++
+	w.last = cv
+	v, err := toFloat(cv)
+	if err != nil || math.IsNaN(v) {
+		return
+	}
+	w.numeric++
+	w.sum += v
+	if w.min == nil || v < w.minV {
+		w.min, w.minV = cv, v
+	}
+	if w.max == nil || v > w.maxV {
+		w.max, w.maxV = cv, v
+	}
+}
+
+// summarise returns the reading summarised by the function, which is stamped with the end of window. Nil is
+// returned if the function is not applicable, e.g. the average of strings.
+func (w *window) summarise(f Function) *models.CommandValue {
+	var cv *models.CommandValue
+	switch f {
+	case Min:
+		cv = copyValue(w.min)
+	case Max:
+		cv = copyValue(w.max)
+	case Last:
+		cv = copyValue(w.last)
+	case Avg:
+		if w.numeric == 0 {
+			return nil
+		}
+		cv = &models.CommandValue{
+			DeviceResourceName: w.last.DeviceResourceName,
+			Type:               common.ValueTypeFloat64,
+			Value:              w.sum / float64(w.numeric),
+			Tags:               make(map[string]string, 1),
+		}
+	}
+	if cv == nil {
+		return nil
+	}
+	cv.Origin = w.end.UnixNano()
+	cv.Tags[TagKey] = string(f)
+	return cv
+}
+
+// copyValue copies the reading with its tags, so that the reading reported by driver is not modified.
+func copyValue(cv *models.CommandValue) *models.CommandValue {
+	if cv == nil {
+		return nil
+	}
+	copied := *cv
+	copied.Tags = make(map[string]string, len(cv.Tags)+1)
+	for k, v := range cv.Tags {
+		copied.Tags[k] = v
+	}
+	return &copied
+}
+
+// toFloat converts the reading of numeric types to float64.
+func toFloat(cv *models.CommandValue) (float64, error) {
+	switch cv.Type {
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64,
+		common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64,
+		common.ValueTypeFloat32, common.ValueTypeFloat64:
+		return cast.ToFloat64E(cv.Value)
+	default:
+		return 0, fmt.Errorf("value of type %s is not a number", cv.Type)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+func newValue(resourceName string, valueType string, value interface{}) *models.CommandValue {
+	cv, _ := models.NewCommandValue(resourceName, valueType, value)
+	return cv
+}
+
+func TestParseRule(t *testing.T) {
+	_, ok, err := ParseRule(nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	rule, ok, err := ParseRule(map[string]interface{}{contracts.AggregateWindowKey: "1s"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, Rule{Window: time.Second, Functions: DefaultFunctions}, rule)
+
+	rule, ok, err = ParseRule(map[string]interface{}{contracts.AggregateWindowKey: 500, contracts.AggregateFunctionsKey: "avg, last"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, Rule{Window: 500 * time.Millisecond, Functions: []Function{Avg, Last}}, rule)
+
+	rule, ok, err = ParseRule(map[string]interface{}{contracts.AggregateWindowKey: "1m", contracts.AggregateFunctionsKey: []interface{}{"max"}})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, Rule{Window: time.Minute, Functions: []Function{Max}}, rule)
+
+	_, ok, err = ParseRule(map[string]interface{}{contracts.AggregateWindowKey: "1s", contracts.AggregateFunctionsKey: "median"})
+	require.Error(t, err)
+	require.False(t, ok)
+
+	_, ok, err = ParseRule(map[string]interface{}{contracts.AggregateWindowKey: "-1s"})
+	require.Error(t, err)
+	require.False(t, ok)
+}
+
+func TestAggregator_Flush(t *testing.T) {
+	a := New()
+	rule := Rule{Window: time.Second, Functions: DefaultFunctions}
+	now := time.Now()
+
+	_, ok := a.Next()
+	require.False(t, ok)
+
+	require.True(t, a.Add("device", "vibration", newValue("x", common.ValueTypeInt16, int16(3)), rule, now))
+	require.False(t, a.Add("device", "vibration", newValue("x", common.ValueTypeInt16, int16(-1)), rule, now))
+	require.False(t, a.Add("device", "vibration", newValue("x", common.ValueTypeInt16, int16(4)), rule, now))
+	require.True(t, a.Add("device", "vibration", newValue("state", common.ValueTypeString, "running"), Rule{Window: 2 * time.Second, Functions: DefaultFunctions}, now))
+
+	next, ok := a.Next()
+	require.True(t, ok)
+	require.Equal(t, now.Add(time.Second), next)
+
+	// the window is not ended yet
+	require.Empty(t, a.Flush(now))
+
+	results := a.Flush(now.Add(time.Second))
+	require.Len(t, results, 4)
+	summaries := make(map[string]*models.CommandValue)
+	for _, values := range results {
+		require.Equal(t, "device", values.DeviceName)
+		require.Equal(t, "vibration", values.SourceName)
+		require.Len(t, values.CommandValues, 1)
+		cv := values.CommandValues[0]
+		require.Equal(t, now.Add(time.Second).UnixNano(), cv.Origin)
+		summaries[cv.Tags[TagKey]] = cv
+	}
+	require.Equal(t, int16(-1), summaries["min"].Value)
+	require.Equal(t, common.ValueTypeInt16, summaries["min"].Type)
+	require.Equal(t, int16(4), summaries["max"].Value)
+	require.Equal(t, int16(4), summaries["last"].Value)
+	require.Equal(t, float64(2), summaries["avg"].Value)
+	require.Equal(t, common.ValueTypeFloat64, summaries["avg"].Type)
+
+	// only the last value of strings is summarised
+	results = a.Flush(now.Add(2 * time.Second))
+	require.Len(t, results, 1)
+	require.Equal(t, "running", results[0].CommandValues[0].Value)
+	require.Equal(t, string(Last), results[0].CommandValues[0].Tags[TagKey])
+
+	_, ok = a.Next()
+	require.False(t, ok)
+}
+
+func TestAggregator_Purge(t *testing.T) {
+	a := New()
+	rule := Rule{Window: time.Second, Functions: []Function{Last}}
+	now := time.Now()
+
+	a.Add("device-001", "source", newValue("x", common.ValueTypeFloat32, float32(1)), rule, now)
+	a.Add("device-002", "source", newValue("x", common.ValueTypeFloat32, float32(2)), rule, now)
+	a.Purge("device-001")
+
+	results := a.Flush(now.Add(time.Second))
+	require.Len(t, results, 1)
+	require.Equal(t, "device-002", results[0].DeviceName)
+
+	var nilAggregator *Aggregator
+	nilAggregator.Purge("device-001")
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buffer
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+// ErrOverflow is returned when the values are dropped since the buffer is full.
+var ErrOverflow = errors.New("the buffer of async values is full")
+
+type key struct {
+	deviceName string
+	sourceName string
+}
+
+// Buffer is a bounded FIFO queue of async values, the values put into a full buffer are handled according to
+// the overflow policy.
+type Buffer struct {
+	capacity int
+	policy   contracts.OverflowPolicy
+	timeout  time.Duration
+	items    *list.List
+	index    map[key]*list.Element // the pending values of each device and source, only for Coalesce
+	mutex    sync.Mutex
+	readable chan struct{}
+	writable chan struct{}
+
+	fullSince time.Time // the time since when the buffer has been full, zero if not full
+	idleSince time.Time // the time since when no values have been taken while some are waiting
+	lostAt    time.Time // the time when the values were lost the last time, zero if never
+
+	Depth metrics.Gauge   // the number of values waiting in the buffer
+	Drops metrics.Counter // the number of values dropped or coalesced
+}
+
+// New creates a buffer with the capacity and overflow policy, the timeout is only used by the Block policy and
+// zero means waiting until there is room.
+func New(capacity int, policy contracts.OverflowPolicy, timeout time.Duration) *Buffer {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	switch policy {
+	case contracts.Block, contracts.DropOldest, contracts.DropNewest, contracts.Coalesce:
+	default:
+		policy = contracts.Block
+	}
+	return &Buffer{
+		capacity: capacity,
+		policy:   policy,
+		timeout:  timeout,
+		items:    list.New(),
+		index:    make(map[key]*list.Element),
+		mutex:    sync.Mutex{},
+		readable: make(chan struct{}, 1),
+		writable: make(chan struct{}, 1),
+		Depth:    metrics.NewGauge(),
+		Drops:    metrics.NewCounter(),
+	}
+}
+
+// Policy returns the overflow policy of the buffer.
+func (b *Buffer) Policy() contracts.OverflowPolicy {
+	return b.policy
+}
+
+// Cap returns the capacity of the buffer.
+func (b *Buffer) Cap() int {
+	return b.capacity
+}
+
+// Len returns the number of values waiting in the buffer.
+func (b *Buffer) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.items.Len()
+}
+
+// Saturated returns how long the buffer has been full continuously, zero if it's not full.
+func (b *Buffer) Saturated() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.fullSince.IsZero() {
+		return 0
+	}
+	return time.Since(b.fullSince)
+}
+
+// LastLoss returns when the values put were lost the last time, i.e. they timed out waiting for room or were
+// rejected by the DropNewest policy, zero if never. The values evicted by DropOldest or replaced by Coalesce are
+// handled as the policies intend, which are not regarded as lost.
+func (b *Buffer) LastLoss() time.Time {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.lostAt
+}
+
+// Stalled returns how long the values have been waiting since the last ones were taken, zero if it's empty.
+func (b *Buffer) Stalled() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.items.Len() == 0 {
+		return 0
+	}
+	return time.Since(b.idleSince)
+}
+
+// Put puts the values into the buffer. ErrOverflow is returned if the values are dropped due to the overflow
+// policy, and the error of ctx is returned if it's done while waiting for room.
+func (b *Buffer) Put(ctx context.Context, values *contracts.AsyncValues) error {
+	if b.policy != contracts.Block {
+		return b.offer(values)
+	}
+
+	var expired <-chan time.Time
+	if b.timeout > 0 {
+		timer := time.NewTimer(b.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		b.mutex.Lock()
+		if b.items.Len() < b.capacity {
+			b.push(values)
+			b.mutex.Unlock()
+			return nil
+		}
+		b.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			b.lose()
+			return ctx.Err()
+		case <-expired:
+			b.lose()
+			return ErrOverflow
+		case <-b.writable:
+		}
+	}
+}
+
+// offer puts the values without waiting.
+func (b *Buffer) offer(values *contracts.AsyncValues) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.items.Len() < b.capacity {
+		b.push(values)
+		return nil
+	}
+
+	b.Drops.Inc(1)
+	switch b.policy {
+	case contracts.DropNewest:
+		b.lostAt = time.Now()
+		return ErrOverflow
+	case contracts.Coalesce:
+		if element, ok := b.index[keyOf(values)]; ok {
+			element.Value = values
+			return nil
+		}
+	}
+	b.remove(b.items.Front())
+	b.push(values)
+	return nil
+}
+
+// Get takes the oldest values from the buffer, it waits until there are values or ctx is done.
+func (b *Buffer) Get(ctx context.Context) (*contracts.AsyncValues, error) {
+	for {
+		b.mutex.Lock()
+		if front := b.items.Front(); front != nil {
+			values := b.remove(front)
+			// only the values taken are progress, the ones dropped by the policy are replaced at once
+			b.idleSince, b.fullSince = time.Now(), time.Time{}
+			b.mutex.Unlock()
+			return values, nil
+		}
+		b.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-b.readable:
+		}
+	}
+}
+
+// lose counts the values which timed out waiting for room as lost.
+func (b *Buffer) lose() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.Drops.Inc(1)
+	b.lostAt = time.Now()
+}
+
+// push appends the values to the buffer, the lock must be held.
+func (b *Buffer) push(values *contracts.AsyncValues) {
+	element := b.items.PushBack(values)
+	if b.policy == contracts.Coalesce {
+		b.index[keyOf(values)] = element
+	}
+	if b.items.Len() == 1 {
+		b.idleSince = time.Now()
+	}
+	if b.items.Len() >= b.capacity && b.fullSince.IsZero() {
+		b.fullSince = time.Now()
+	}
+	b.Depth.Update(int64(b.items.Len()))
+	notify(b.readable)
+	if b.items.Len() < b.capacity {
+		notify(b.writable)
+	}
+}
+
+// remove removes the element from the buffer, the lock must be held.
+func (b *Buffer) remove(element *list.Element) *contracts.AsyncValues {
+	values := b.items.Remove(element).(*contracts.AsyncValues)
+	if b.policy == contracts.Coalesce {
+		// the index may refer to the values which replaced the removed ones
+		k := keyOf(values)
+		if b.index[k] == element {
+			delete(b.index, k)
+		}
+	}
+	b.Depth.Update(int64(b.items.Len()))
+	notify(b.writable)
+	if b.items.Len() > 0 {
+		notify(b.readable)
+	}
+	return values
+}
+
+func keyOf(values *contracts.AsyncValues) key {
+	return key{deviceName: values.DeviceName, sourceName: values.SourceName}
+}
+
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buffer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+func values(deviceName string, sourceName string) *contracts.AsyncValues {
+	return &contracts.AsyncValues{DeviceName: deviceName, SourceName: sourceName}
+}
+
+func drain(t *testing.T, b *Buffer) []string {
+	sources := make([]string, 0)
+	for b.Len() > 0 {
+		v, err := b.Get(context.Background())
+		require.NoError(t, err)
+		sources = append(sources, v.DeviceName+"/"+v.SourceName)
+	}
+	return sources
+}
+
+func TestBuffer_Policy(t *testing.T) {
+	tests := []struct {
+		policy contracts.OverflowPolicy
+		err    error
+		want   []string
+		drops  int64
+	}{
+		{policy: contracts.DropNewest, err: ErrOverflow, want: []string{"d1/a", "d1/b"}, drops: 2},
+		{policy: contracts.DropOldest, want: []string{"d1/a", "d2/a"}, drops: 2},
+		{policy: contracts.Coalesce, want: []string{"d1/b", "d2/a"}, drops: 2},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			b := New(2, tt.policy, 0)
+			require.Equal(t, tt.policy, b.Policy())
+			require.NoError(t, b.Put(context.Background(), values("d1", "a")))
+			require.NoError(t, b.Put(context.Background(), values("d1", "b")))
+			require.Equal(t, int64(2), b.Depth.Value())
+
+			require.ErrorIs(t, b.Put(context.Background(), values("d1", "a")), tt.err)
+			require.ErrorIs(t, b.Put(context.Background(), values("d2", "a")), tt.err)
+			require.Equal(t, tt.want, drain(t, b))
+			require.Equal(t, tt.drops, b.Drops.Count())
+			require.Equal(t, int64(0), b.Depth.Value())
+		})
+	}
+}
+
+func TestBuffer_Coalesce(t *testing.T) {
+	b := New(2, contracts.Coalesce, 0)
+	first, latest := values("d1", "a"), values("d1", "a")
+	require.NoError(t, b.Put(context.Background(), first))
+	require.NoError(t, b.Put(context.Background(), values("d2", "a")))
+	require.NoError(t, b.Put(context.Background(), latest))
+
+	v, err := b.Get(context.Background())
+	require.NoError(t, err)
+	require.Same(t, latest, v)
+	require.NoError(t, b.Put(context.Background(), values("d1", "a")))
+	require.Equal(t, []string{"d2/a", "d1/a"}, drain(t, b))
+}
+
+func TestBuffer_Block(t *testing.T) {
+	b := New(1, contracts.Block, time.Millisecond*20)
+	require.NoError(t, b.Put(context.Background(), values("d1", "a")))
+	require.ErrorIs(t, b.Put(context.Background(), values("d1", "b")), ErrOverflow)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, b.Put(ctx, values("d1", "b")), context.Canceled)
+	require.Equal(t, int64(2), b.Drops.Count())
+
+	// wait until there is room
+	b = New(1, contracts.Block, 0)
+	require.NoError(t, b.Put(context.Background(), values("d1", "a")))
+	done := make(chan error)
+	go func() {
+		done <- b.Put(context.Background(), values("d1", "b"))
+	}()
+	time.Sleep(time.Millisecond * 10)
+	require.Equal(t, []string{"d1/a"}, drain(t, b))
+	require.NoError(t, <-done)
+	require.Equal(t, []string{"d1/b"}, drain(t, b))
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	_, err := b.Get(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBuffer_Concurrent(t *testing.T) {
+	b := New(4, contracts.Block, 0)
+	const total = 1000
+	for i := 0; i < 4; i++ {
+		go func() {
+			for j := 0; j < total/4; j++ {
+				_ = b.Put(context.Background(), values("d1", "a"))
+			}
+		}()
+	}
+	for i := 0; i < total; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err := b.Get(ctx)
+		cancel()
+		require.NoError(t, err)
+	}
+	require.Equal(t, 0, b.Len())
+}
+
+func TestBuffer_SaturatedAndStalled(t *testing.T) {
+	b := New(2, contracts.DropOldest, 0)
+	require.Zero(t, b.Saturated())
+	require.Zero(t, b.Stalled())
+
+	require.NoError(t, b.Put(context.Background(), values("d1", "a")))
+	require.NoError(t, b.Put(context.Background(), values("d1", "b")))
+	time.Sleep(time.Millisecond * 20)
+	require.GreaterOrEqual(t, b.Saturated(), time.Millisecond*20)
+	require.GreaterOrEqual(t, b.Stalled(), time.Millisecond*20)
+
+	// the values dropped by the policy are not progress
+	require.NoError(t, b.Put(context.Background(), values("d1", "c")))
+	require.GreaterOrEqual(t, b.Stalled(), time.Millisecond*20)
+	require.GreaterOrEqual(t, b.Saturated(), time.Millisecond*20)
+
+	_, err := b.Get(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, b.Saturated())
+	require.Less(t, b.Stalled(), time.Millisecond*20)
+	_, err = b.Get(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, b.Stalled())
+}
+
+func TestBuffer_LastLoss(t *testing.T) {
+	ctx := context.Background()
+	for _, policy := range []contracts.OverflowPolicy{contracts.DropOldest, contracts.Coalesce} {
+		b := New(1, policy, 0)
+		require.NoError(t, b.Put(ctx, values("device-001", "a")))
+		require.NoError(t, b.Put(ctx, values("device-001", "a")))
+		require.True(t, b.LastLoss().IsZero(), policy)
+	}
+
+	b := New(1, contracts.DropNewest, 0)
+	require.NoError(t, b.Put(ctx, values("device-001", "a")))
+	require.ErrorIs(t, b.Put(ctx, values("device-001", "b")), ErrOverflow)
+	require.WithinDuration(t, time.Now(), b.LastLoss(), time.Second)
+
+	b = New(1, contracts.Block, time.Millisecond)
+	require.NoError(t, b.Put(ctx, values("device-001", "a")))
+	require.ErrorIs(t, b.Put(ctx, values("device-001", "b")), ErrOverflow)
+	require.WithinDuration(t, time.Now(), b.LastLoss(), time.Second)
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+// Cache coalesces the concurrent reads of the same device resource into one invocation of driver, and keeps
+// the results for a short TTL so that the following reads can be served without accessing the device.
+type Cache struct {
+	entries map[key]*entry
+	calls   map[key]*call
+	mutex   sync.Mutex
+}
+
+type key struct {
+	deviceName   string
+	resourceName string
+}
+
+type entry struct {
+	result contracts.Result
+	expire time.Time
+}
+
+type call struct {
+	done   chan struct{}
+	result contracts.Result
+	err    error
+	ttl    time.Duration
+}
+
+func New() *Cache {
+	return &Cache{
+		entries: make(map[key]*entry),
+		calls:   make(map[key]*call),
+		mutex:   sync.Mutex{},
+	}
+}
+
+// Begin serves the requests from the cache if the results are not expired, and joins the reads in flight of the
+// same resources. The TTL of each request is given by ttl, zero means neither cached nor coalesced.
+// The returned Flight must be completed once the missed requests have been handled.
+func (c *Cache) Begin(deviceName string, reqs []contracts.ReadRequest, ttl func(contracts.ReadRequest) time.Duration) *Flight {
+	flight := &Flight{cache: c, deviceName: deviceName}
+	if c == nil {
+		flight.misses = reqs
+		return flight
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for _, req := range reqs {
+		t := ttl(req)
+		if t <= 0 {
+			flight.misses = append(flight.misses, req)
+			flight.owned = append(flight.owned, nil)
+			continue
+		}
+		k := key{deviceName: deviceName, resourceName: req.Native().DeviceResourceName}
+		if e, ok := c.entries[k]; ok {
+			if now.Before(e.expire) {
+				req.SetResult(e.result)
+				continue
+			}
+			delete(c.entries, k)
+		}
+		if cl, ok := c.calls[k]; ok {
+			flight.joined = append(flight.joined, joined{req: req, call: cl})
+			continue
+		}
+		cl := &call{done: make(chan struct{}), ttl: t}
+		c.calls[k] = cl
+		flight.misses = append(flight.misses, req)
+		flight.owned = append(flight.owned, cl)
+	}
+	return flight
+}
+
+// Purge removes the cached results of the device.
+func (c *Cache) Purge(deviceName string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for k := range c.entries {
+		if k.deviceName == deviceName {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Evict removes the cached results of the resources of the device, e.g. once they are written.
+func (c *Cache) Evict(deviceName string, resourceNames ...string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, resourceName := range resourceNames {
+		delete(c.entries, key{deviceName: deviceName, resourceName: resourceName})
+	}
+}
+
+// Flight is the reads of a batch of requests which are not served by the cache.
+type Flight struct {
+	cache      *Cache
+	deviceName string
+	misses     []contracts.ReadRequest
+	owned      []*call
+	joined     []joined
+	completed  bool
+}
+
+type joined struct {
+	req  contracts.ReadRequest
+	call *call
+}
+
+// Misses returns the requests which should be handled by the driver.
+func (f *Flight) Misses() []contracts.ReadRequest {
+	return f.misses
+}
+
+// Wait waits for the reads in flight joined by the requests, and then takes their outcomes.
+func (f *Flight) Wait(ctx context.Context) error {
+	for _, j := range f.joined {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-j.call.done:
+		}
+		switch {
+		case j.call.err != nil:
+			j.req.Failed(j.call.err)
+		case j.call.result != nil:
+			j.req.SetResult(j.call.result)
+		default:
+			j.req.Skip()
+		}
+	}
+	return nil
+}
+
+// Complete caches the results of the missed requests, and wakes up the joined reads. The error is shared
+// with the joined reads if the driver failed. Only the first call takes effect.
+func (f *Flight) Complete(err error) {
+	if f.cache == nil || f.completed {
+		return
+	}
+	f.completed = true
+
+	f.cache.mutex.Lock()
+	defer f.cache.mutex.Unlock()
+
+	now := time.Now()
+	for i, req := range f.misses {
+		cl := f.owned[i]
+		if cl == nil {
+			continue
+		}
+		k := key{deviceName: f.deviceName, resourceName: req.Native().DeviceResourceName}
+		delete(f.cache.calls, k)
+
+		cl.err = err
+		if err == nil && req.Error() != nil {
+			cl.err = req.Error()
+		}
+		if cl.err == nil && req.Result() != nil {
+			cl.result = newCachedResult(req.Result(), now)
+			req.SetResult(cl.result)
+			f.cache.entries[k] = &entry{result: cl.result, expire: now.Add(cl.ttl)}
+		}
+		close(cl.done)
+	}
+}
+
+// cachedResult keeps the origin timestamp of the result, so that the result served from the cache is
+// still stamped with the time it was read.
+type cachedResult struct {
+	contracts.Result
+	origin int64
+}
+
+func newCachedResult(result contracts.Result, now time.Time) *cachedResult {
+	origin := result.UnixNano()
+	if origin == 0 {
+		origin = now.UnixNano()
+	}
+	return &cachedResult{Result: result, origin: origin}
+}
+
+func (r *cachedResult) UnixNano() int64 {
+	return r.origin
+}
+
+func (r *cachedResult) CommandValue(resourceName string, valueType string) (*models.CommandValue, error) {
+	cv, err := r.Result.CommandValue(resourceName, valueType)
+	if cv == nil {
+		return cv, err
+	}
+	// the command value may be shared, copy it before stamping
+	copied := *cv
+	copied.Origin = r.origin
+	return &copied, err
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+func newRequests(names ...string) []contracts.ReadRequest {
+	reqs := make([]contracts.ReadRequest, 0, len(names))
+	for _, name := range names {
+		reqs = append(reqs, contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: name, Type: common.ValueTypeInt32}))
+	}
+	return reqs
+}
+
+func fixedTTL(ttl time.Duration) func(contracts.ReadRequest) time.Duration {
+	return func(contracts.ReadRequest) time.Duration {
+		return ttl
+	}
+}
+
+func TestCache_Hit(t *testing.T) {
+	c := New()
+
+	reqs := newRequests("temperature", "humidity")
+	flight := c.Begin("device", reqs, fixedTTL(time.Minute))
+	require.Len(t, flight.Misses(), 2)
+	origin := time.Now().Add(-time.Second)
+	reqs[0].SetResult(contracts.NewSimpleResult(int32(1)).WithTime(origin))
+	reqs[1].Failed(errors.New("read failed"))
+	flight.Complete(nil)
+
+	reqs = newRequests("temperature", "humidity")
+	flight = c.Begin("device", reqs, fixedTTL(time.Minute))
+	require.Len(t, flight.Misses(), 1)
+	require.Equal(t, "humidity", flight.Misses()[0].Native().DeviceResourceName)
+	cv, err := reqs[0].Result().CommandValue("temperature", common.ValueTypeInt32)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), cv.Value)
+	require.Equal(t, origin.UnixNano(), cv.Origin)
+	flight.Complete(nil)
+
+	// other devices are not affected by purge
+	c.Purge("other")
+	flight = c.Begin("device", newRequests("temperature"), fixedTTL(time.Minute))
+	require.Len(t, flight.Misses(), 0)
+	c.Purge("device")
+	flight = c.Begin("device", newRequests("temperature"), fixedTTL(time.Minute))
+	require.Len(t, flight.Misses(), 1)
+	flight.Misses()[0].SetResult(contracts.NewSimpleResult(int32(2)))
+	flight.Complete(nil)
+
+	// only the evicted resources of the device are read again
+	reqs = newRequests("temperature", "humidity")
+	flight = c.Begin("device", reqs, fixedTTL(time.Minute))
+	reqs[1].SetResult(contracts.NewSimpleResult(int32(3)))
+	flight.Complete(nil)
+	c.Evict("other", "temperature")
+	c.Evict("device", "humidity")
+	flight = c.Begin("device", newRequests("temperature", "humidity"), fixedTTL(time.Minute))
+	require.Len(t, flight.Misses(), 1)
+	require.Equal(t, "humidity", flight.Misses()[0].Native().DeviceResourceName)
+	flight.Complete(nil)
+}
+
+func TestCache_Expire(t *testing.T) {
+	c := New()
+
+	reqs := newRequests("temperature")
+	flight := c.Begin("device", reqs, fixedTTL(time.Millisecond*10))
+	reqs[0].SetResult(contracts.NewSimpleResult(int32(1)))
+	flight.Complete(nil)
+
+	cv, err := reqs[0].Result().CommandValue("temperature", common.ValueTypeInt32)
+	require.NoError(t, err)
+	require.NotZero(t, cv.Origin)
+
+	time.Sleep(time.Millisecond * 20)
+	flight = c.Begin("device", newRequests("temperature"), fixedTTL(time.Millisecond*10))
+	require.Len(t, flight.Misses(), 1)
+	flight.Complete(nil)
+
+	// never cached without TTL
+	flight = c.Begin("device", newRequests("temperature"), fixedTTL(0))
+	require.Len(t, flight.Misses(), 1)
+	flight.Complete(nil)
+	require.Len(t, c.entries, 0)
+}
+
+func TestCache_Coalesce(t *testing.T) {
+	c := New()
+
+	first := newRequests("temperature", "humidity")
+	flight1 := c.Begin("device", first, fixedTTL(time.Minute))
+	require.Len(t, flight1.Misses(), 2)
+
+	second := newRequests("temperature", "humidity", "pressure")
+	flight2 := c.Begin("device", second, fixedTTL(time.Minute))
+	require.Len(t, flight2.Misses(), 1)
+
+	go func() {
+		first[0].SetResult(contracts.NewSimpleResult(int32(1)))
+		first[1].Failed(errors.New("read failed"))
+		flight1.Complete(nil)
+	}()
+	require.NoError(t, flight2.Wait(context.Background()))
+	require.Equal(t, int32(1), second[0].Result().Value())
+	require.Error(t, second[1].Error())
+	flight2.Complete(nil)
+
+	// the error of driver is shared
+	first = newRequests("pressure")
+	flight1 = c.Begin("device", first, fixedTTL(time.Minute))
+	second = newRequests("pressure")
+	flight2 = c.Begin("device", second, fixedTTL(time.Minute))
+	flight1.Complete(errors.New("driver failed"))
+	require.NoError(t, flight2.Wait(context.Background()))
+	require.Error(t, second[0].Error())
+
+	// give up waiting
+	flight1 = c.Begin("device", newRequests("voltage"), fixedTTL(time.Minute))
+	flight2 = c.Begin("device", newRequests("voltage"), fixedTTL(time.Minute))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	require.ErrorIs(t, flight2.Wait(ctx), context.DeadlineExceeded)
+	flight1.Complete(nil)
+}
+
+func TestCache_Nil(t *testing.T) {
+	var c *Cache
+	reqs := newRequests("temperature")
+	flight := c.Begin("device", reqs, fixedTTL(time.Minute))
+	require.Equal(t, reqs, flight.Misses())
+	require.NoError(t, flight.Wait(context.Background()))
+	flight.Complete(nil)
+	c.Purge("device")
+}
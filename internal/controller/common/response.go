@@ -0,0 +1,48 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+// WriteErrorResponse writes the EdgeX error as a BaseResponse in json format with the status code of the error.
+func WriteErrorResponse(w http.ResponseWriter, edgexErr errors.EdgeX) {
+	logger.D.Errorf("%v", edgexErr.Error())
+	responses := common.NewBaseResponse("", edgexErr.Error(), edgexErr.Code())
+	WriteResponse(w, edgexErr.Code(), responses)
+}
+
+// WriteResponse writes the data in json format with the status code.
+func WriteResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	enc := json.NewEncoder(w)
+	err := enc.Encode(data)
+	if err != nil {
+		logger.D.Errorf("Error encoding the data: " + err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -24,10 +24,10 @@ import (
 	"sync"
 	"time"
 
-	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 
 	"github.com/volcengine/vei-driver-sdk-go/extension/requests"
+	"github.com/volcengine/vei-driver-sdk-go/internal/controller/common"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
@@ -45,13 +45,13 @@ func Discover(discovery interfaces.Discovery) func(writer http.ResponseWriter, r
 	return func(writer http.ResponseWriter, request *http.Request) {
 		if discovery == nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindNotImplemented, "Please implement the discovery interface", nil)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
 		if !mutex.TryLock() {
 			edgexErr := errors.NewCommonEdgeX(errors.KindContractInvalid, "Please wait for the last operation complete", nil)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
@@ -61,14 +61,14 @@ func Discover(discovery interfaces.Discovery) func(writer http.ResponseWriter, r
 		body, err := io.ReadAll(request.Body)
 		if err != nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindServerError, "failed to read request body", err)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
 		param := &requests.DiscoveryParameter{}
 		if err = json.Unmarshal(body, param); err != nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindServerError, "failed to parse request body", err)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
@@ -104,18 +104,3 @@ func Discover(discovery interfaces.Discovery) func(writer http.ResponseWriter, r
 		}
 	}
 }
-
-func WriteErrorResponse(w http.ResponseWriter, edgexErr errors.EdgeX) {
-	logger.D.Errorf("%v", edgexErr.Error())
-	responses := common.NewBaseResponse("", edgexErr.Error(), edgexErr.Code())
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(edgexErr.Code())
-
-	enc := json.NewEncoder(w)
-	err := enc.Encode(responses)
-	if err != nil {
-		logger.D.Errorf("Error encoding the data: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/controller/common"
+)
+
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// Check is a named check of the probe, which returns an error if the check fails.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Report is the outcome of the probe, the result of each check is either StatusUp or the error.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Run runs the checks concurrently, and the checks not finished within the timeout are regarded as failed,
+// e.g. the driver is deadlocked.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) Report {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]string, len(checks))}
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			result := StatusUp
+			if err := run(ctx, check); err != nil {
+				result = err.Error()
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Checks[check.Name] = result
+			if result != StatusUp {
+				report.Status = StatusDown
+			}
+		}(check)
+	}
+	wg.Wait()
+	return report
+}
+
+// run waits for the check until ctx is done, the panic raised by the check is regarded as a failure.
+func run(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- check.Check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Probe responds the report of the checks in json format, with the status code 503 if any check fails.
+func Probe(checks func() []Check, timeout time.Duration) func(writer http.ResponseWriter, request *http.Request) {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		report := Run(request.Context(), checks(), timeout)
+		code := http.StatusOK
+		if report.Status != StatusUp {
+			code = http.StatusServiceUnavailable
+		}
+		common.WriteResponse(writer, code, report)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	report := Run(context.Background(), nil, time.Second)
+	require.Equal(t, Report{Status: StatusUp, Checks: map[string]string{}}, report)
+
+	checks := []Check{
+		{Name: "ok", Check: func(context.Context) error { return nil }},
+		{Name: "failed", Check: func(context.Context) error { return errors.New("disconnected") }},
+		{Name: "panic", Check: func(context.Context) error { panic("oops") }},
+		{Name: "deadlock", Check: func(context.Context) error { select {} }},
+	}
+	report = Run(context.Background(), checks, 50*time.Millisecond)
+	require.Equal(t, StatusDown, report.Status)
+	require.Equal(t, map[string]string{
+		"ok":       StatusUp,
+		"failed":   "disconnected",
+		"panic":    "panic: oops",
+		"deadlock": context.DeadlineExceeded.Error(),
+	}, report.Checks)
+}
+
+func TestProbe(t *testing.T) {
+	var err error
+	handler := Probe(func() []Check {
+		return []Check{{Name: "driver", Check: func(context.Context) error { return err }}}
+	}, time.Second)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/health/ready", http.NoBody))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"status":"UP","checks":{"driver":"UP"}}`, recorder.Body.String())
+
+	err = errors.New("not ready")
+	recorder = httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/health/ready", http.NoBody))
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.JSONEq(t, `{"status":"DOWN","checks":{"driver":"not ready"}}`, recorder.Body.String())
+}
@@ -21,11 +21,11 @@ import (
 	"io"
 	"net/http"
 
-	"github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 
+	"github.com/volcengine/vei-driver-sdk-go/internal/controller/common"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces"
-	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
 )
 
 type StreamRequest struct {
@@ -39,7 +39,7 @@ func OnStreamNotFound(webhook interfaces.Webhook) func(writer http.ResponseWrite
 	return func(writer http.ResponseWriter, request *http.Request) {
 		if webhook == nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindNotImplemented, "Please implement the webhook interface", nil)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
@@ -47,21 +47,21 @@ func OnStreamNotFound(webhook interfaces.Webhook) func(writer http.ResponseWrite
 		body, err := io.ReadAll(request.Body)
 		if err != nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindServerError, "failed to read request body", err)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
 		param := &StreamRequest{}
 		if err = json.Unmarshal(body, param); err != nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindServerError, "failed to parse request body", err)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
 		ctx := request.Context()
 		if err = webhook.OnStreamNotFound(ctx, param.Schema, param.Stream); err != nil {
-			edgexErr := errors.NewCommonEdgeX(errors.KindServerError, "failed to execute webhook", err)
-			WriteErrorResponse(writer, edgexErr)
+			edgexErr := errors.NewCommonEdgeX(contracts.EdgeXKindOf(err), "failed to execute webhook", err)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
@@ -73,7 +73,7 @@ func OnStreamNoneReader(webhook interfaces.Webhook) func(writer http.ResponseWri
 	return func(writer http.ResponseWriter, request *http.Request) {
 		if webhook == nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindNotImplemented, "Please implement the webhook interface", nil)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
@@ -81,39 +81,24 @@ func OnStreamNoneReader(webhook interfaces.Webhook) func(writer http.ResponseWri
 		body, err := io.ReadAll(request.Body)
 		if err != nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindServerError, "failed to read request body", err)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
 		param := &StreamRequest{}
 		if err = json.Unmarshal(body, param); err != nil {
 			edgexErr := errors.NewCommonEdgeX(errors.KindServerError, "failed to parse request body", err)
-			WriteErrorResponse(writer, edgexErr)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
 		ctx := request.Context()
 		if err = webhook.OnStreamNoneReader(ctx, param.Schema, param.Stream); err != nil {
-			edgexErr := errors.NewCommonEdgeX(errors.KindServerError, "failed to execute webhook", err)
-			WriteErrorResponse(writer, edgexErr)
+			edgexErr := errors.NewCommonEdgeX(contracts.EdgeXKindOf(err), "failed to execute webhook", err)
+			common.WriteErrorResponse(writer, edgexErr)
 			return
 		}
 
 		writer.WriteHeader(http.StatusOK)
 	}
 }
-
-func WriteErrorResponse(w http.ResponseWriter, edgexErr errors.EdgeX) {
-	logger.D.Errorf("%v", edgexErr.Error())
-	responses := common.NewBaseResponse("", edgexErr.Error(), edgexErr.Code())
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(edgexErr.Code())
-
-	enc := json.NewEncoder(w)
-	err := enc.Encode(responses)
-	if err != nil {
-		logger.D.Errorf("Error encoding the data: " + err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dispatcher
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Dispatcher executes the commands of the same device one at a time, and the commands of different devices
+// concurrently up to the limit. The waiting commands are served in the order of arrival, a command is only
+// overtaken by the later ones of other devices when its own device is busy.
+type Dispatcher struct {
+	limit   int
+	running int
+	busy    map[string]time.Time // the devices executing commands, and since when they have been
+	depths  map[string]int
+	pending *list.List
+	mutex   sync.Mutex
+
+	Depth    metrics.Gauge   // the number of commands waiting in the queue
+	Timeouts metrics.Counter // the number of commands given up waiting in the queue
+}
+
+type waiter struct {
+	deviceName string
+	ready      chan struct{}
+}
+
+func NewDispatcher(limit int) *Dispatcher {
+	if limit <= 0 {
+		limit = 1
+	}
+	return &Dispatcher{
+		limit:    limit,
+		busy:     make(map[string]time.Time),
+		depths:   make(map[string]int),
+		pending:  list.New(),
+		mutex:    sync.Mutex{},
+		Depth:    metrics.NewGauge(),
+		Timeouts: metrics.NewCounter(),
+	}
+}
+
+// Acquire waits until the commands of the device can be executed, the returned function must be called once
+// the execution completes. The error of the context is returned if it is done before the turn comes.
+func (d *Dispatcher) Acquire(ctx context.Context, deviceName string) (func(), error) {
+	w := &waiter{deviceName: deviceName, ready: make(chan struct{})}
+
+	d.mutex.Lock()
+	elem := d.pending.PushBack(w)
+	d.depths[deviceName]++
+	d.Depth.Update(int64(d.pending.Len()))
+	d.schedule()
+	d.mutex.Unlock()
+
+	select {
+	case <-w.ready:
+		return d.releaser(deviceName), nil
+	case <-ctx.Done():
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	select {
+	case <-w.ready:
+		// the turn comes at the same time, give it back
+		d.release(deviceName)
+	default:
+		d.pending.Remove(elem)
+		d.dequeued(deviceName)
+	}
+	d.Timeouts.Inc(1)
+	return nil, ctx.Err()
+}
+
+// DeviceDepth returns the number of commands of the device waiting in the queue.
+func (d *Dispatcher) DeviceDepth(deviceName string) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.depths[deviceName]
+}
+
+// Longest returns the device executing a command for the longest time and the duration, the name is empty if no
+// command is being executed.
+func (d *Dispatcher) Longest() (string, time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	var deviceName string
+	var since time.Time
+	for name, t := range d.busy {
+		if deviceName == "" || t.Before(since) {
+			deviceName, since = name, t
+		}
+	}
+	if deviceName == "" {
+		return "", 0
+	}
+	return deviceName, time.Since(since)
+}
+
+func (d *Dispatcher) releaser(deviceName string) func() {
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			d.mutex.Lock()
+			defer d.mutex.Unlock()
+			d.release(deviceName)
+		})
+	}
+}
+
+func (d *Dispatcher) release(deviceName string) {
+	delete(d.busy, deviceName)
+	d.running--
+	d.schedule()
+}
+
+func (d *Dispatcher) dequeued(deviceName string) {
+	d.depths[deviceName]--
+	if d.depths[deviceName] <= 0 {
+		delete(d.depths, deviceName)
+	}
+	d.Depth.Update(int64(d.pending.Len()))
+}
+
+// schedule wakes up the waiters in order as long as the limit is not reached, it must be called with the lock held.
+func (d *Dispatcher) schedule() {
+	for elem := d.pending.Front(); elem != nil && d.running < d.limit; {
+		next := elem.Next()
+		w := elem.Value.(*waiter)
+		if _, busy := d.busy[w.deviceName]; !busy {
+			d.pending.Remove(elem)
+			d.dequeued(w.deviceName)
+			d.busy[w.deviceName] = time.Now()
+			d.running++
+			close(w.ready)
+		}
+		elem = next
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher_SerializeDevice(t *testing.T) {
+	d := NewDispatcher(4)
+
+	var running, maxRunning int32
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := d.Acquire(context.Background(), "device")
+			require.NoError(t, err)
+			defer release()
+
+			n := atomic.AddInt32(&running, 1)
+			if n > atomic.LoadInt32(&maxRunning) {
+				atomic.StoreInt32(&maxRunning, n)
+			}
+			time.Sleep(time.Millisecond * 5)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), maxRunning)
+	require.Equal(t, int64(0), d.Depth.Value())
+}
+
+func TestDispatcher_Limit(t *testing.T) {
+	d := NewDispatcher(2)
+
+	var running, maxRunning int32
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			release, err := d.Acquire(context.Background(), fmt.Sprintf("device-%d", i))
+			require.NoError(t, err)
+			defer release()
+
+			n := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond * 10)
+			atomic.AddInt32(&running, -1)
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, int32(2), maxRunning)
+}
+
+func TestDispatcher_FairOrdering(t *testing.T) {
+	d := NewDispatcher(1)
+	release, err := d.Acquire(context.Background(), "device-1")
+	require.NoError(t, err)
+
+	order := make(chan string, 3)
+	for _, name := range []string{"device-2", "device-3", "device-1"} {
+		go func(name string) {
+			r, err := d.Acquire(context.Background(), name)
+			require.NoError(t, err)
+			order <- name
+			r()
+		}(name)
+		// make sure the waiters arrive in order
+		require.Eventually(t, func() bool { return d.DeviceDepth(name) == 1 }, time.Second, time.Millisecond)
+	}
+	require.Equal(t, int64(3), d.Depth.Value())
+
+	release()
+	require.Equal(t, "device-2", <-order)
+	require.Equal(t, "device-3", <-order)
+	require.Equal(t, "device-1", <-order)
+}
+
+func TestDispatcher_Timeout(t *testing.T) {
+	d := NewDispatcher(1)
+	release, err := d.Acquire(context.Background(), "device")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	_, err = d.Acquire(ctx, "device")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int64(1), d.Timeouts.Count())
+	require.Equal(t, 0, d.DeviceDepth("device"))
+
+	// release twice is harmless
+	release()
+	release()
+	release, err = d.Acquire(context.Background(), "device")
+	require.NoError(t, err)
+	release()
+}
+
+func TestDispatcher_Longest(t *testing.T) {
+	d := NewDispatcher(2)
+	name, duration := d.Longest()
+	require.Equal(t, "", name)
+	require.Zero(t, duration)
+
+	release1, err := d.Acquire(context.Background(), "device-1")
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond * 20)
+	release2, err := d.Acquire(context.Background(), "device-2")
+	require.NoError(t, err)
+
+	name, duration = d.Longest()
+	require.Equal(t, "device-1", name)
+	require.GreaterOrEqual(t, duration, time.Millisecond*20)
+
+	release1()
+	name, _ = d.Longest()
+	require.Equal(t, "device-2", name)
+	release2()
+	name, _ = d.Longest()
+	require.Equal(t, "", name)
+}
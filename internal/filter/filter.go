@@ -0,0 +1,219 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/spf13/cast"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+// Config is the reporting filter of a device resource, which is parsed from the attributes of the resource.
+type Config struct {
+	// OnChange reports the reading only if its value differs from the last reported one.
+	OnChange bool
+	// Deadband is the minimum absolute change of the numeric reading to be reported.
+	Deadband float64
+	// DeadbandPercent is the minimum change of the numeric reading to be reported, in percent of the last
+	// reported value.
+	DeadbandPercent float64
+	// MaxSilence is the maximum interval without any reading reported, the reading is reported as a heartbeat
+	// once it exceeds even if the value is unchanged. Zero means no heartbeat.
+	MaxSilence time.Duration
+}
+
+// Enabled checks whether the readings are filtered by the config.
+func (c Config) Enabled() bool {
+	return c.OnChange || c.Deadband > 0 || c.DeadbandPercent > 0
+}
+
+// ParseConfig parses the config of filter from the attributes 'reportOnChange', 'deadband', 'deadbandPercent'
+// and 'maxSilence'. The attributes with invalid values are ignored.
+func ParseConfig(attributes map[string]interface{}) Config {
+	var config Config
+	if attributes == nil {
+		return config
+	}
+	if v, ok := attributes[contracts.ReportOnChangeKey]; ok {
+		config.OnChange, _ = cast.ToBoolE(v)
+	}
+	if v, ok := attributes[contracts.DeadbandKey]; ok {
+		config.Deadband, _ = cast.ToFloat64E(v)
+	}
+	if v, ok := attributes[contracts.DeadbandPercentKey]; ok {
+		config.DeadbandPercent, _ = cast.ToFloat64E(v)
+	}
+	if v, ok := attributes[contracts.MaxSilenceKey]; ok {
+		config.MaxSilence, _ = utils.CastDuration(v)
+	}
+	return config
+}
+
+// Filter drops the readings which are not changed enough since the last reported ones, the last reported
+// value of each device resource is kept.
+type Filter struct {
+	states map[key]*state
+	locks  map[string]*sync.Mutex // the locks of devices held from checking the readings until recording them
+	mutex  sync.Mutex
+}
+
+type key struct {
+	deviceName   string
+	resourceName string
+}
+
+type state struct {
+	value    interface{}
+	reported time.Time
+}
+
+func New() *Filter {
+	return &Filter{
+		states: make(map[key]*state),
+		locks:  make(map[string]*sync.Mutex),
+		mutex:  sync.Mutex{},
+	}
+}
+
+// Allow checks whether the reading of the device should be reported at now according to the config parsed from
+// attributes, and records it as the last reported one if so. The readings are always allowed by a nil filter or
+// if the filter of the resource is not enabled.
+func (f *Filter) Allow(deviceName string, cv *models.CommandValue, attributes map[string]interface{}, now time.Time) bool {
+	if !f.Check(deviceName, cv, attributes, now) {
+		return false
+	}
+	f.Record(deviceName, cv, attributes, now)
+	return true
+}
+
+// Check checks whether the reading of the device should be reported at now like Allow, but it is not recorded
+// until Record is called, e.g. once the reading is accepted for delivery.
+func (f *Filter) Check(deviceName string, cv *models.CommandValue, attributes map[string]interface{}, now time.Time) bool {
+	if f == nil || cv == nil {
+		return true
+	}
+	config := ParseConfig(attributes)
+	if !config.Enabled() {
+		return true
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	last, ok := f.states[key{deviceName: deviceName, resourceName: cv.DeviceResourceName}]
+	return !ok || config.exceeds(last, cv.Value, now)
+}
+
+// Record records the reading of the device as the last reported one at now, nothing is recorded if the filter
+// of the resource is not enabled.
+func (f *Filter) Record(deviceName string, cv *models.CommandValue, attributes map[string]interface{}, now time.Time) {
+	if f == nil || cv == nil || !ParseConfig(attributes).Enabled() {
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.states[key{deviceName: deviceName, resourceName: cv.DeviceResourceName}] = &state{value: cv.Value, reported: now}
+}
+
+// Lock locks the readings of the device until the returned function is called, so that the readings checked are
+// recorded before the following ones of the device are checked, e.g. the equal readings reported concurrently
+// are not allowed twice.
+func (f *Filter) Lock(deviceName string) func() {
+	if f == nil {
+		return func() {}
+	}
+	f.mutex.Lock()
+	lock, ok := f.locks[deviceName]
+	if !ok {
+		lock = &sync.Mutex{}
+		f.locks[deviceName] = lock
+	}
+	f.mutex.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+// Purge removes the last reported values of the device.
+func (f *Filter) Purge(deviceName string) {
+	if f == nil {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for k := range f.states {
+		if k.deviceName == deviceName {
+			delete(f.states, k)
+		}
+	}
+}
+
+// exceeds checks whether the value is changed enough since the last reported one, or the heartbeat is due.
+func (c Config) exceeds(last *state, value interface{}, now time.Time) bool {
+	if c.MaxSilence > 0 && now.Sub(last.reported) >= c.MaxSilence {
+		return true
+	}
+
+	previous, ok1 := toFloat(last.value)
+	current, ok2 := toFloat(value)
+	if !ok1 || !ok2 || c.Deadband <= 0 && c.DeadbandPercent <= 0 {
+		return !equal(last.value, value)
+	}
+	if math.IsNaN(previous) || math.IsNaN(current) {
+		return math.IsNaN(previous) != math.IsNaN(current)
+	}
+
+	delta := math.Abs(current - previous)
+	if c.Deadband > 0 && delta > c.Deadband {
+		return true
+	}
+	// any change exceeds the percentage of zero
+	if c.DeadbandPercent > 0 && delta > math.Abs(previous)*c.DeadbandPercent/100 {
+		return true
+	}
+	return false
+}
+
+// toFloat converts the numeric value to float64, the values of other types are not converted.
+func toFloat(value interface{}) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func equal(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	// the values of objects may be decoded into different types
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+func newValue(value interface{}) *models.CommandValue {
+	return &models.CommandValue{DeviceResourceName: "temperature", Value: value}
+}
+
+func TestParseConfig(t *testing.T) {
+	config := ParseConfig(nil)
+	require.False(t, config.Enabled())
+
+	config = ParseConfig(map[string]interface{}{
+		contracts.ReportOnChangeKey:  "true",
+		contracts.DeadbandKey:        0.5,
+		contracts.DeadbandPercentKey: "10",
+		contracts.MaxSilenceKey:      "1m",
+	})
+	require.Equal(t, Config{OnChange: true, Deadband: 0.5, DeadbandPercent: 10, MaxSilence: time.Minute}, config)
+	require.True(t, config.Enabled())
+
+	config = ParseConfig(map[string]interface{}{contracts.MaxSilenceKey: 1000, contracts.DeadbandKey: "invalid"})
+	require.Equal(t, Config{MaxSilence: time.Second}, config)
+	require.False(t, config.Enabled())
+}
+
+func TestFilter_OnChange(t *testing.T) {
+	f := New()
+	attributes := map[string]interface{}{contracts.ReportOnChangeKey: true}
+	now := time.Now()
+
+	require.True(t, f.Allow("device", newValue("on"), attributes, now))
+	require.False(t, f.Allow("device", newValue("on"), attributes, now))
+	require.True(t, f.Allow("device", newValue("off"), attributes, now))
+	require.True(t, f.Allow("other", newValue("off"), attributes, now))
+
+	// not filtered without the attributes
+	require.True(t, f.Allow("device", newValue("off"), nil, now))
+
+	f.Purge("device")
+	require.True(t, f.Allow("device", newValue("off"), attributes, now))
+	require.False(t, f.Allow("other", newValue("off"), attributes, now))
+
+	var nilFilter *Filter
+	require.True(t, nilFilter.Allow("device", newValue("off"), attributes, now))
+	nilFilter.Purge("device")
+}
+
+func TestFilter_CheckAndRecord(t *testing.T) {
+	f := New()
+	now := time.Now()
+	attributes := map[string]interface{}{contracts.ReportOnChangeKey: true}
+
+	// nothing is recorded by the check
+	require.True(t, f.Check("device", newValue("on"), attributes, now))
+	require.True(t, f.Check("device", newValue("on"), attributes, now))
+	f.Record("device", newValue("on"), attributes, now)
+	require.False(t, f.Check("device", newValue("on"), attributes, now))
+	require.True(t, f.Check("device", newValue("off"), attributes, now))
+
+	// nothing is recorded if the filter is not enabled
+	f.Record("device", newValue("off"), nil, now)
+	require.False(t, f.Check("device", newValue("on"), attributes, now))
+}
+
+func TestFilter_Lock(t *testing.T) {
+	f := New()
+	now := time.Now()
+	attributes := map[string]interface{}{contracts.ReportOnChangeKey: true}
+
+	var wg sync.WaitGroup
+	var allowed int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := f.Lock("device")
+			defer unlock()
+			if f.Check("device", newValue("on"), attributes, now) {
+				atomic.AddInt32(&allowed, 1)
+				f.Record("device", newValue("on"), attributes, now)
+			}
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, 1, allowed)
+
+	// the devices are locked separately
+	unlock := f.Lock("device")
+	f.Lock("other")()
+	unlock()
+
+	var nilFilter *Filter
+	nilFilter.Lock("device")()
+}
+
+func TestFilter_Deadband(t *testing.T) {
+	f := New()
+	attributes := map[string]interface{}{contracts.DeadbandKey: 1}
+	now := time.Now()
+
+	require.True(t, f.Allow("device", newValue(float32(20)), attributes, now))
+	require.False(t, f.Allow("device", newValue(float32(20.5)), attributes, now))
+	require.False(t, f.Allow("device", newValue(float32(19)), attributes, now))
+	// compared with the last reported value, so that slow drifts are reported eventually
+	require.True(t, f.Allow("device", newValue(float32(21.5)), attributes, now))
+	require.False(t, f.Allow("device", newValue(float32(21)), attributes, now))
+
+	// values of other types are reported on change
+	require.True(t, f.Allow("device", newValue("error"), attributes, now))
+	require.False(t, f.Allow("device", newValue("error"), attributes, now))
+}
+
+func TestFilter_DeadbandPercent(t *testing.T) {
+	f := New()
+	attributes := map[string]interface{}{contracts.DeadbandPercentKey: 10}
+	now := time.Now()
+
+	require.True(t, f.Allow("device", newValue(int32(100)), attributes, now))
+	require.False(t, f.Allow("device", newValue(int32(110)), attributes, now))
+	require.True(t, f.Allow("device", newValue(int32(111)), attributes, now))
+	require.False(t, f.Allow("device", newValue(int32(100)), attributes, now))
+	require.True(t, f.Allow("device", newValue(int32(99)), attributes, now))
+
+	// any change of zero is reported
+	f.Purge("device")
+	require.True(t, f.Allow("device", newValue(uint8(0)), attributes, now))
+	require.False(t, f.Allow("device", newValue(uint8(0)), attributes, now))
+	require.True(t, f.Allow("device", newValue(uint8(1)), attributes, now))
+}
+
+func TestFilter_MaxSilence(t *testing.T) {
+	f := New()
+	attributes := map[string]interface{}{contracts.ReportOnChangeKey: true, contracts.MaxSilenceKey: "1m"}
+	now := time.Now()
+
+	require.True(t, f.Allow("device", newValue(true), attributes, now))
+	require.False(t, f.Allow("device", newValue(true), attributes, now.Add(30*time.Second)))
+	// heartbeat
+	require.True(t, f.Allow("device", newValue(true), attributes, now.Add(time.Minute)))
+	require.False(t, f.Allow("device", newValue(true), attributes, now.Add(90*time.Second)))
+	require.True(t, f.Allow("device", newValue(true), attributes, now.Add(2*time.Minute)))
+}
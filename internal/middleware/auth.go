@@ -0,0 +1,146 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	dtos "github.com/edgexfoundry/go-mod-core-contracts/v2/dtos/common"
+	"github.com/gorilla/mux"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/controller/common"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+const (
+	HeaderAuthorization = "Authorization"
+	HeaderTimestamp     = "X-Timestamp"
+	HeaderSignature     = "X-Signature"
+	HeaderMediaSecret   = "X-Media-Secret"
+	QueryMediaSecret    = "secret"
+
+	BearerPrefix = "Bearer "
+	// the maximum difference between the timestamp of a signed request and now.
+	MaxTimestampSkew = time.Minute * 5
+)
+
+// Authenticate authenticates the requests according to the auth mode of config. In bearer mode, the header
+// 'Authorization: Bearer <token>' is required. In HMAC mode, the header 'X-Timestamp' of unix seconds and the
+// header 'X-Signature' are required, see Sign for the signature.
+func Authenticate(config *Config) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			var err error
+			switch config.AuthMode {
+			case AuthBearer:
+				err = verifyBearer(request, config.AuthToken)
+			case AuthHMAC:
+				err = verifyHMAC(request, config.AuthToken, time.Now())
+			}
+			if err != nil {
+				unauthorized(writer, request, err)
+				return
+			}
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// VerifyMediaSecret verifies the requests of the hooks from media server, the secret is carried in the query
+// 'secret' or the header 'X-Media-Secret' and must be the same as the MediaSecret. The requests are rejected
+// if no secret is configured.
+func VerifyMediaSecret(secret func() string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			expected := secret()
+			actual := request.URL.Query().Get(QueryMediaSecret)
+			if actual == "" {
+				actual = request.Header.Get(HeaderMediaSecret)
+			}
+			if expected == "" || !equal(actual, expected) {
+				unauthorized(writer, request, errors.New("invalid media secret"))
+				return
+			}
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the method, the request URI, the timestamp and the body joined
+// by line breaks with the key.
+func Sign(key string, method string, uri string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(method + "\n" + uri + "\n" + timestamp + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func verifyBearer(request *http.Request, token string) error {
+	authorization := request.Header.Get(HeaderAuthorization)
+	if len(authorization) <= len(BearerPrefix) || authorization[:len(BearerPrefix)] != BearerPrefix {
+		return errors.New("missing bearer token")
+	}
+	if !equal(authorization[len(BearerPrefix):], token) {
+		return errors.New("invalid bearer token")
+	}
+	return nil
+}
+
+func verifyHMAC(request *http.Request, key string, now time.Time) error {
+	timestamp := request.Header.Get(HeaderTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp '%s'", timestamp)
+	}
+	if skew := now.Sub(time.Unix(seconds, 0)); skew > MaxTimestampSkew || skew < -MaxTimestampSkew {
+		return fmt.Errorf("timestamp '%s' is expired", timestamp)
+	}
+
+	var body []byte
+	if request.Body != nil {
+		if body, err = io.ReadAll(request.Body); err != nil {
+			return fmt.Errorf("read body failed: %w", err)
+		}
+		_ = request.Body.Close()
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	signature := Sign(key, request.Method, request.URL.RequestURI(), timestamp, body)
+	if !equal(request.Header.Get(HeaderSignature), signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func equal(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func unauthorized(writer http.ResponseWriter, request *http.Request, err error) {
+	logger.D.Warnf("reject the unauthorized request '%s %s' from %s: %v", request.Method, request.URL.Path, request.RemoteAddr, err)
+	response := dtos.NewBaseResponse("", err.Error(), http.StatusUnauthorized)
+	common.WriteResponse(writer, http.StatusUnauthorized, response)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func serve(handler http.Handler, request *http.Request) int {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder.Result().StatusCode
+}
+
+var ok = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+	writer.WriteHeader(http.StatusOK)
+})
+
+func TestAuthenticateBearer(t *testing.T) {
+	handler := Authenticate(&Config{AuthMode: AuthBearer, AuthToken: "token"})(ok)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v2/logging?level=debug", http.NoBody)
+	require.Equal(t, http.StatusUnauthorized, serve(handler, request))
+	request.Header.Set(HeaderAuthorization, "Bearer wrong")
+	require.Equal(t, http.StatusUnauthorized, serve(handler, request))
+	request.Header.Set(HeaderAuthorization, "Bearer token")
+	require.Equal(t, http.StatusOK, serve(handler, request))
+}
+
+func TestAuthenticateHMAC(t *testing.T) {
+	handler := Authenticate(&Config{AuthMode: AuthHMAC, AuthToken: "key"})(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			body := make([]byte, 4)
+			n, _ := request.Body.Read(body)
+			require.Equal(t, "body", string(body[:n]))
+			writer.WriteHeader(http.StatusOK)
+		}))
+
+	newRequest := func(timestamp time.Time, key string) *http.Request {
+		ts := strconv.FormatInt(timestamp.Unix(), 10)
+		request := httptest.NewRequest(http.MethodPost, "/api/v2/device/discovery?x=1", strings.NewReader("body"))
+		request.Header.Set(HeaderTimestamp, ts)
+		request.Header.Set(HeaderSignature, Sign(key, http.MethodPost, "/api/v2/device/discovery?x=1", ts, []byte("body")))
+		return request
+	}
+	require.Equal(t, http.StatusOK, serve(handler, newRequest(time.Now(), "key")))
+	require.Equal(t, http.StatusUnauthorized, serve(handler, newRequest(time.Now(), "wrong")))
+	require.Equal(t, http.StatusUnauthorized, serve(handler, newRequest(time.Now().Add(-time.Hour), "key")))
+	require.Equal(t, http.StatusUnauthorized, serve(handler, httptest.NewRequest(http.MethodGet, "/", http.NoBody)))
+}
+
+func TestVerifyMediaSecret(t *testing.T) {
+	secret := ""
+	handler := VerifyMediaSecret(func() string { return secret })(ok)
+
+	require.Equal(t, http.StatusUnauthorized, serve(handler, httptest.NewRequest(http.MethodPost, "/hook?secret=", http.NoBody)))
+
+	secret = "media-secret"
+	require.Equal(t, http.StatusUnauthorized, serve(handler, httptest.NewRequest(http.MethodPost, "/hook?secret=wrong", http.NoBody)))
+	require.Equal(t, http.StatusOK, serve(handler, httptest.NewRequest(http.MethodPost, "/hook?secret=media-secret", http.NoBody)))
+	request := httptest.NewRequest(http.MethodPost, "/hook", http.NoBody)
+	request.Header.Set(HeaderMediaSecret, "media-secret")
+	require.Equal(t, http.StatusOK, serve(handler, request))
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+)
+
+// AuthMode is the mode to authenticate the requests to the customized server.
+type AuthMode string
+
+const (
+	AuthNone   AuthMode = "none"
+	AuthBearer AuthMode = "bearer"
+	AuthHMAC   AuthMode = "hmac"
+)
+
+// Config is the security config of the customized server, which can be loaded from the driver config.
+type Config struct {
+	// the files of the certificate and key in PEM format, TLS is enabled if specified.
+	TLSCertFile string `json:"HTTPTLSCertFile"`
+	TLSKeyFile  string `json:"HTTPTLSKeyFile"`
+	// the certificate and key in PEM format, which take precedence over the files.
+	TLSCert string `json:"HTTPTLSCert"`
+	TLSKey  string `json:"HTTPTLSKey"`
+	// the mode to authenticate the requests, none by default.
+	AuthMode AuthMode `json:"HTTPAuthMode"`
+	// the token of bearer, or the key to sign the requests in HMAC mode.
+	AuthToken string `json:"HTTPAuthToken"`
+}
+
+// LoadConfig loads the security config from the driver config.
+func LoadConfig(configs map[string]string) (*Config, error) {
+	data, err := json.Marshal(configs)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Merge overrides the config with the non-empty fields of other.
+func (c *Config) Merge(other Config) {
+	if other.TLSCertFile != "" || other.TLSCert != "" {
+		c.TLSCertFile, c.TLSKeyFile, c.TLSCert, c.TLSKey = other.TLSCertFile, other.TLSKeyFile, other.TLSCert, other.TLSKey
+	}
+	if other.AuthMode != "" {
+		c.AuthMode, c.AuthToken = other.AuthMode, other.AuthToken
+	}
+}
+
+// Validate checks whether the config is complete.
+func (c *Config) Validate() error {
+	switch c.AuthMode {
+	case "", AuthNone:
+	case AuthBearer, AuthHMAC:
+		if c.AuthToken == "" {
+			return fmt.Errorf("the token of auth mode '%s' is missing", c.AuthMode)
+		}
+	default:
+		return fmt.Errorf("unsupported auth mode '%s'", c.AuthMode)
+	}
+	return nil
+}
+
+// Authenticated indicates whether the requests should be authenticated.
+func (c *Config) Authenticated() bool {
+	return c.AuthMode == AuthBearer || c.AuthMode == AuthHMAC
+}
+
+// TLSConfig returns the config of TLS, nil is returned if TLS is not enabled.
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	var (
+		cert tls.Certificate
+		err  error
+	)
+	switch {
+	case c.TLSCert != "":
+		cert, err = tls.X509KeyPair([]byte(c.TLSCert), []byte(c.TLSKey))
+	case c.TLSCertFile != "":
+		cert, err = tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load the certificate and key failed: %w", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
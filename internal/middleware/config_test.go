@@ -0,0 +1,101 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// generateCertificate generates a self-signed certificate and key in PEM format for tests.
+func generateCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return string(cert), string(keyPem)
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(map[string]string{
+		"MediaSecret":   "secret",
+		"HTTPAuthMode":  "bearer",
+		"HTTPAuthToken": "token",
+	})
+	require.NoError(t, err)
+	require.Equal(t, AuthBearer, config.AuthMode)
+	require.True(t, config.Authenticated())
+	require.NoError(t, config.Validate())
+
+	config.Merge(Config{AuthMode: AuthHMAC})
+	require.Error(t, config.Validate())
+	config.Merge(Config{AuthMode: AuthMode("basic"), AuthToken: "token"})
+	require.Error(t, config.Validate())
+
+	config, err = LoadConfig(nil)
+	require.NoError(t, err)
+	require.False(t, config.Authenticated())
+	require.NoError(t, config.Validate())
+}
+
+func TestTLSConfig(t *testing.T) {
+	config := &Config{}
+	tlsConfig, err := config.TLSConfig()
+	require.NoError(t, err)
+	require.Nil(t, tlsConfig)
+
+	cert, key := generateCertificate(t)
+	config.TLSCert, config.TLSKey = cert, key
+	tlsConfig, err = config.TLSConfig()
+	require.NoError(t, err)
+	require.Len(t, tlsConfig.Certificates, 1)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cert.pem"), []byte(cert), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.pem"), []byte(key), 0600))
+	config = &Config{}
+	config.Merge(Config{TLSCertFile: filepath.Join(dir, "cert.pem"), TLSKeyFile: filepath.Join(dir, "key.pem")})
+	tlsConfig, err = config.TLSConfig()
+	require.NoError(t, err)
+	require.NotNil(t, tlsConfig)
+
+	config.TLSKeyFile = filepath.Join(dir, "missing.pem")
+	_, err = config.TLSConfig()
+	require.Error(t, err)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+// setAdminState records the admin state of the device, and returns whether the device is locked or unlocked by it.
+func (a *Agent) setAdminState(deviceName string, state models.AdminState) bool {
+	a.lockMutex.Lock()
+	defer a.lockMutex.Unlock()
+	locked := state == models.Locked
+	if a.locked[deviceName] == locked {
+		return false
+	}
+	if !locked {
+		delete(a.locked, deviceName)
+		return true
+	}
+	if a.locked == nil {
+		a.locked = make(map[string]bool)
+	}
+	a.locked[deviceName] = true
+	return true
+}
+
+// isLocked returns whether the device is locked by the operator, the locked device is excluded from the status
+// reporting and polling.
+func (a *Agent) isLocked(deviceName string) bool {
+	a.lockMutex.RLock()
+	defer a.lockMutex.RUnlock()
+	return a.locked[deviceName]
+}
+
+// wrapDevice wraps the device with the admin state recorded.
+func (a *Agent) wrapDevice(deviceName string, protocols map[string]models.ProtocolProperties) *contracts.Device {
+	device := contracts.WrapDevice(deviceName, protocols)
+	device.AdminState = contracts.UNLOCKED
+	if a.isLocked(deviceName) {
+		device.AdminState = contracts.LOCKED
+	}
+	return device
+}
+
+// handleAdminState calls OnLocked or OnUnlocked of the AdminStateHandler according to the admin state of the
+// device, so that the driver can release or restore the connections of the device.
+func (a *Agent) handleAdminState(device *contracts.Device) {
+	if a.admin == nil {
+		return
+	}
+	operation, callback := "OnUnlocked", a.admin.OnUnlocked
+	if device.IsLocked() {
+		operation, callback = "OnLocked", a.admin.OnLocked
+	}
+	if err := a.handleDevice(device, operation, callback); err != nil {
+		a.log.Warnf("device '%s' handles %s failed: %v", device.Name, operation, err)
+	}
+}
+
+// deferDevice records the device locked when added, which is not added to the driver until it is unlocked.
+func (a *Agent) deferDevice(deviceName string) {
+	a.lockMutex.Lock()
+	defer a.lockMutex.Unlock()
+	if a.deferred == nil {
+		a.deferred = make(map[string]bool)
+	}
+	a.deferred[deviceName] = true
+}
+
+// isDeferred returns whether the device has not been added to the driver since it was locked when added.
+func (a *Agent) isDeferred(deviceName string) bool {
+	a.lockMutex.RLock()
+	defer a.lockMutex.RUnlock()
+	return a.deferred[deviceName]
+}
+
+// forgetDeferred forgets the deferred device, and returns whether it was deferred.
+func (a *Agent) forgetDeferred(deviceName string) bool {
+	a.lockMutex.Lock()
+	defer a.lockMutex.Unlock()
+	deferred := a.deferred[deviceName]
+	delete(a.deferred, deviceName)
+	return deferred
+}
+
+// handleLockedDevices calls OnLocked for the devices already locked when the driver is initialized.
+func (a *Agent) handleLockedDevices() {
+	if a.admin == nil {
+		return
+	}
+	for _, device := range a.service.Devices() {
+		if a.isLocked(device.Name) {
+			a.handleAdminState(a.wrapDevice(device.Name, device.Protocols))
+		}
+	}
+}
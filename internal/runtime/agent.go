@@ -18,15 +18,28 @@ package runtime
 
 import (
 	"context"
+	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	sdkinterfaces "github.com/edgexfoundry/device-sdk-go/v2/pkg/interfaces"
 	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/service"
 	lc "github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/rcrowley/go-metrics"
 
+	"github.com/volcengine/vei-driver-sdk-go/internal/aggregate"
+	"github.com/volcengine/vei-driver-sdk-go/internal/buffer"
+	"github.com/volcengine/vei-driver-sdk-go/internal/cache"
+	"github.com/volcengine/vei-driver-sdk-go/internal/dispatcher"
+	"github.com/volcengine/vei-driver-sdk-go/internal/filter"
+	"github.com/volcengine/vei-driver-sdk-go/internal/middleware"
+	"github.com/volcengine/vei-driver-sdk-go/internal/scheduler"
+	"github.com/volcengine/vei-driver-sdk-go/internal/spool"
 	"github.com/volcengine/vei-driver-sdk-go/internal/status"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interceptor"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/media"
@@ -34,23 +47,63 @@ import (
 )
 
 type Agent struct {
-	name      string
-	version   string
-	driver    interfaces.Driver
-	handler   interfaces.DeviceHandler
-	discovery interfaces.Discovery
-	debugger  interfaces.Debugger
-	webhook   interfaces.Webhook
-	reporter  interfaces.Reporter
-	service   sdkinterfaces.DeviceServiceSDK
-	asyncCh   chan<- *sdkmodels.AsyncValues // used by agent
-	deviceCh  chan<- []sdkmodels.DiscoveredDevice
-	log       logger.Logger
-
-	ctx   context.Context
-	stop  context.CancelFunc
-	wg    *sync.WaitGroup
-	async chan *contracts.AsyncValues // used by driver
+	name       string
+	version    string
+	driver     interfaces.Driver
+	ctxDriver  interfaces.ContextDriver
+	handler    interfaces.DeviceHandler
+	admin      interfaces.AdminStateHandler
+	subscriber interfaces.EventSubscriber
+	poller     interfaces.Poller
+	health     interfaces.HealthChecker
+	readiness  interfaces.ReadinessChecker
+	watcher    interfaces.ConfigWatcher
+	discovery  interfaces.Discovery
+	debugger   interfaces.Debugger
+	webhook    interfaces.Webhook
+	reporter   interfaces.Reporter
+	service    sdkinterfaces.DeviceServiceSDK
+	server     *http.Server
+	asyncCh    chan<- *sdkmodels.AsyncValues // used by agent
+	deviceCh   chan<- []sdkmodels.DiscoveredDevice
+	log        logger.Logger
+
+	ctx    context.Context
+	stop   context.CancelFunc
+	wg     *sync.WaitGroup
+	async  chan *contracts.AsyncValues // used by driver
+	buffer *buffer.Buffer              // the async values waiting to be forwarded
+
+	spool          *spool.Spool // the async values waiting for replay during the outages of delivery
+	spoolSignal    chan struct{}
+	deliveryPassed time.Time // the time of the last passed check of the delivery backend
+	deliveryMutex  sync.Mutex
+
+	events     map[string]map[string]contracts.EventRequest // subscribed events of each device
+	eventMutex sync.Mutex
+	dispatcher *dispatcher.Dispatcher
+	cache      *cache.Cache
+
+	filter          *filter.Filter // the last reported readings of each device resource
+	filteredCounter metrics.Counter
+
+	aggregator      *aggregate.Aggregator // the windows of the async readings being summarised
+	aggregateSignal chan struct{}
+
+	scheduler *scheduler.Scheduler // the polling of devices if the driver implements Poller
+
+	locked    map[string]bool // the devices locked by the operator
+	deferred  map[string]bool // the devices locked when added, which are added to the driver once unlocked
+	lockMutex sync.RWMutex
+
+	initialized int32 // set once the driver is initialized, and cleared when it stops
+
+	driverConfigs atomic.Value // the map[string]string of driver configs in use, updated once the Driver section changes
+	configMutex   sync.Mutex   // serializes the changes of the driver configs, which are read without the lock
+
+	panics       map[string]int64 // the number of panics raised by driver of each device
+	panicMutex   sync.Mutex
+	panicCounter metrics.Counter
 
 	// if the driver is in strict mode, any error in request will be returned, ignored otherwise.
 	StrictMode bool
@@ -58,6 +111,63 @@ type Agent struct {
 	OfflineDecision status.OfflineDecision
 	// if no StatusManager is specified, a default one will be initialized
 	StatusManager interfaces.StatusManager
+	// the default deadline of each invocation of ContextDriver if no timeout attribute is defined for the
+	// resources, zero means no deadline. The deadline applies to the whole batch of requests, which is the longest
+	// timeout attribute of the resources if any.
+	CommandTimeout time.Duration
+	// the maximum number of devices handling commands concurrently, the commands of the same device are always
+	// handled one at a time. If not specified, it will be read from the env 'DEVICE_MAXCONCURRENCY'.
+	MaxConcurrency int
+	// the maximum duration of a command waiting for its turn in the queue, zero means waiting until its turn.
+	QueueTimeout time.Duration
+	// the policy to retry the failed requests, no retry by default.
+	RetryPolicy contracts.RetryPolicy
+	// the TTL of the cached read results which can be overridden by the attribute 'cacheTTL' of resources,
+	// the concurrent reads of the same resources are coalesced if the TTL is positive. Zero disables the cache.
+	ReadCacheTTL time.Duration
+	// the interceptors wrapping the invocations of driver and the reports of async values in order, the first
+	// one is the outermost.
+	Interceptors []interceptor.Interceptor
+	// if true, the parameters of writes are passed to the driver without checking against the device profile.
+	SkipWriteValidation bool
+	// if true, the transform properties of resources, i.e. mask, shift, base, scale and offset, are applied by the
+	// agent. The DataTransform of the device service must be disabled then, otherwise the properties are applied twice.
+	TransformProperties bool
+	// if true, the attributes 'mask', 'shift', 'base', 'scale' and 'offset' of resources are applied by the agent,
+	// which take precedence over the transform properties of the same names.
+	TransformAttributes bool
+	// if true, the reads of the resources polled by the AutoEvents of devices are filtered by the attributes
+	// 'reportOnChange', 'deadband', 'deadbandPercent' and 'maxSilence' like the async readings. The device service
+	// reads the AutoEvents the same way as core-command, so the reads of these resources by core-command are
+	// filtered as well.
+	FilterAutoEvents bool
+	// the address listened by the customized server, ':9999' by default.
+	HTTPAddress string
+	// the maximum duration to drain the connections of the customized server when stopping.
+	HTTPShutdownTimeout time.Duration
+	// the extra routes of driver served by the customized server.
+	Routes []Route
+	// the policy to handle the async values reported when the buffer is full, Block by default.
+	OverflowPolicy contracts.OverflowPolicy
+	// the maximum duration of the Block policy waiting for room, zero means waiting until there is room.
+	OverflowTimeout time.Duration
+	// the options of the durable spool of async values, the spool is disabled if no directory is specified.
+	SpoolOptions spool.Options
+	// the maximum duration of delivering the async values before they are spooled, only used with the spool.
+	DeliveryTimeout time.Duration
+	// the check of the backend receiving the events, the values are spooled while it fails. If not specified, the
+	// message bus or core-data configured by the env overrides of EdgeX is dialed. Only used with the spool.
+	DeliveryCheck func(ctx context.Context) error
+	// the TLS and authentication of the customized server, which override the ones in the driver config.
+	HTTPSecurity middleware.Config
+	// the duration for which the async queue is full before the driver is not ready, 30s by default. The driver is
+	// also not ready for the duration after the async values are lost.
+	SaturationPeriod time.Duration
+	// the duration without progress of a command or the forwarding of async values before the driver is not alive,
+	// 5m by default. It should be longer than the CommandTimeout including the retries.
+	StallTimeout time.Duration
+	// the ratio of the random jitter to the interval of polling, 0.1 by default. Only used if the driver implements Poller.
+	PollJitter float64
 }
 
 func (a *Agent) Initialize(_ lc.LoggingClient, asyncCh chan<- *sdkmodels.AsyncValues,
@@ -75,11 +185,56 @@ func (a *Agent) Initialize(_ lc.LoggingClient, asyncCh chan<- *sdkmodels.AsyncVa
 
 	bufferSize := utils.GetIntEnv("DEVICE_ASYNCBUFFERSIZE", 10)
 	a.async = make(chan *contracts.AsyncValues, bufferSize)
+	a.buffer = buffer.New(int(bufferSize), a.OverflowPolicy, a.OverflowTimeout)
+	a.RegisterMetric("DriverAsyncQueueDepth", a.buffer.Depth)
+	a.RegisterMetric("DriverAsyncDrops", a.buffer.Drops)
+	a.filter = filter.New()
+	a.filteredCounter = metrics.NewCounter()
+	a.RegisterMetric("DriverFilteredReadings", a.filteredCounter)
+	a.aggregator, a.aggregateSignal = aggregate.New(), make(chan struct{}, 1)
+	if a.SpoolOptions.Dir != "" {
+		s, err := spool.Open(a.SpoolOptions)
+		if err != nil {
+			a.log.Errorf("Open the spool in '%s' failed: %v", a.SpoolOptions.Dir, err)
+			return err
+		}
+		a.spool, a.spoolSignal = s, make(chan struct{}, 1)
+		a.RegisterMetric("DriverSpoolSize", a.spool.Size)
+		a.RegisterMetric("DriverSpoolPending", a.spool.Pending)
+		a.RegisterMetric("DriverSpoolDrops", a.spool.Drops)
+		a.log.Infof("Open the spool in '%s', %d values are waiting for replay", a.SpoolOptions.Dir, a.spool.Len())
+		if a.DeliveryCheck == nil {
+			if address := deliveryBackend(); address != "" {
+				a.DeliveryCheck = dialCheck(address)
+				a.log.Infof("Check the delivery backend '%s' before delivering the async values", address)
+			} else {
+				a.log.Warnf("No delivery backend is configured, the outages of delivery are only detected by the delivery timeout")
+			}
+		}
+	}
 	go a.HandleAsyncResults(a.ctx, a.wg)
-	a.log.Infof("Set async buffer size: %d", bufferSize)
+	a.log.Infof("Set async buffer size: %d, overflow policy: %s, timeout: %v", bufferSize, a.buffer.Policy(), a.OverflowTimeout)
+
+	if a.MaxConcurrency <= 0 {
+		a.MaxConcurrency = int(utils.GetIntEnv("DEVICE_MAXCONCURRENCY", 16))
+	}
+	a.dispatcher = dispatcher.NewDispatcher(a.MaxConcurrency)
+	a.RegisterMetric("DriverQueueDepth", a.dispatcher.Depth)
+	a.RegisterMetric("DriverQueueTimeouts", a.dispatcher.Timeouts)
+	a.log.Infof("Set max concurrency: %d, queue timeout: %v", a.MaxConcurrency, a.QueueTimeout)
+
+	a.panicCounter = metrics.NewCounter()
+	a.RegisterMetric("DriverPanics", a.panicCounter)
+
+	a.cache = cache.New()
+	a.log.Infof("Set read cache TTL: %v", a.ReadCacheTTL)
 
 	deviceNames := make([]string, 0)
 	for _, device := range a.service.Devices() {
+		// The locked devices are excluded from the status reporting until they are unlocked.
+		if a.setAdminState(device.Name, device.AdminState) {
+			continue
+		}
 		deviceNames = append(deviceNames, device.Name)
 	}
 
@@ -93,6 +248,7 @@ func (a *Agent) Initialize(_ lc.LoggingClient, asyncCh chan<- *sdkmodels.AsyncVa
 			a.log.Infof("New status manager with offline decision: %+v", a.OfflineDecision)
 		}
 	}
+	a.StatusManager = status.Exclude(a.StatusManager, a.isLocked)
 
 	if err := a.RegisterRoutes(); err != nil {
 		return err
@@ -103,14 +259,61 @@ func (a *Agent) Initialize(_ lc.LoggingClient, asyncCh chan<- *sdkmodels.AsyncVa
 		a.log.Errorf("Initailize media config failed: %v", err)
 		return err
 	}
+	a.applyErrorLanguage(a.service.DriverConfigs())
+	a.watchDriverConfig()
+
+	if err := a.initializeDriver(); err != nil {
+		return err
+	}
+	a.handleLockedDevices()
+	a.startPolling()
+	atomic.StoreInt32(&a.initialized, 1)
+	return nil
+}
 
+func (a *Agent) initializeDriver() (err error) {
+	defer a.recoverPanic("", "Initialize", &err)
 	return a.driver.Initialize(a.log, a.async)
 }
 
 func (a *Agent) Stop(force bool) error {
 	a.log.Infof("Driver %s is stopping...", a.name)
+	atomic.StoreInt32(&a.initialized, 0)
 	a.stop()
+	if err := a.ShutdownServer(); err != nil {
+		a.log.Warnf("Shutdown customized server failed: %v", err)
+	}
 	a.log.Infof("Wait for all goroutines stop...")
 	a.wg.Wait()
+	a.scheduler.Wait()
+	if a.spool != nil {
+		if err := a.spool.Close(); err != nil {
+			a.log.Warnf("Close the spool failed: %v", err)
+		}
+	}
+	return a.stopDriver(force)
+}
+
+func (a *Agent) stopDriver(force bool) (err error) {
+	defer a.recoverPanic("", "Stop", &err)
 	return a.driver.Stop(force)
 }
+
+// context returns the context of the agent which is done when the agent stops.
+func (a *Agent) context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// RegisterMetric registers the metric to the metrics manager of the device service, so that it can be published
+// through the telemetry of EdgeX.
+func (a *Agent) RegisterMetric(name string, metric interface{}) {
+	if a.service == nil || a.service.GetMetricsManager() == nil {
+		return
+	}
+	if err := a.service.GetMetricsManager().Register(name, metric, map[string]string{"driver": a.name}); err != nil {
+		a.log.Warnf("register metric '%s' failed: %v", name, err)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/aggregate"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+// aggregateValues puts the readings of the resources with an aggregation rule into their windows, and returns
+// the others to be reported as they are. Nil is returned if all the readings are aggregated.
+func (a *Agent) aggregateValues(event *contracts.AsyncValues) *contracts.AsyncValues {
+	if a.aggregator == nil || len(event.CommandValues) == 0 {
+		return event
+	}
+
+	now := time.Now()
+	attributes := a.resourceAttributes(event.DeviceName)
+	raw := make([]*sdkmodels.CommandValue, 0, len(event.CommandValues))
+	for _, cv := range event.CommandValues {
+		if cv == nil {
+			raw = append(raw, cv)
+			continue
+		}
+		rule, ok, err := aggregate.ParseRule(attributes(cv.DeviceResourceName))
+		if err != nil {
+			a.log.Warnf("invalid aggregation of '%s' of device '%s', report the readings as they are: %v",
+				cv.DeviceResourceName, event.DeviceName, err)
+		}
+		if !ok {
+			raw = append(raw, cv)
+			continue
+		}
+		if a.aggregator.Add(event.DeviceName, event.SourceName, cv, rule, now) {
+			select {
+			case a.aggregateSignal <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	if len(raw) == len(event.CommandValues) {
+		return event
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	return &contracts.AsyncValues{DeviceName: event.DeviceName, SourceName: event.SourceName, CommandValues: raw}
+}
+
+// flushAggregates puts the summarised readings into the buffer once their windows end.
+func (a *Agent) flushAggregates(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	defer wg.Done()
+
+	for a.waitAggregates(ctx) {
+		for _, values := range a.aggregator.Flush(time.Now()) {
+			if err := a.buffer.Put(ctx, values); err != nil {
+				a.log.Warnf("drop the aggregated values of device '%s', policy: %s, error: %v", values.DeviceName, a.buffer.Policy(), err)
+			}
+		}
+	}
+}
+
+// waitAggregates waits for the end of the earliest window, or a new window started. False is returned if ctx is done.
+func (a *Agent) waitAggregates(ctx context.Context) bool {
+	var timeout <-chan time.Time
+	if next, ok := a.aggregator.Next(); ok {
+		timer := time.NewTimer(time.Until(next))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timeout:
+	case <-a.aggregateSignal:
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spf13/cast"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/media"
+)
+
+const (
+	DriverConfigSection = "Driver"
+	// ErrorLanguageKey is the key of the driver config to select the language of the messages of errors, which
+	// are shown as the reasons of the device status, 'zh' by default.
+	ErrorLanguageKey = "ErrorLanguage"
+)
+
+// driverConfig is the custom config loaded before watching the Driver section, which is required by the device
+// service to watch any section of the configuration provider.
+type driverConfig struct {
+	Driver map[string]string
+}
+
+func (c *driverConfig) UpdateFromRaw(rawConfig interface{}) bool {
+	config, ok := rawConfig.(*driverConfig)
+	if ok {
+		c.Driver = config.Driver
+	}
+	return ok
+}
+
+// watchDriverConfig listens for the changes of the Driver section in the configuration provider, nothing is
+// watched if the configuration provider is not enabled.
+func (a *Agent) watchDriverConfig() {
+	a.configMutex.Lock()
+	a.driverConfigs.Store(copyConfigs(a.service.DriverConfigs()))
+	a.configMutex.Unlock()
+
+	if err := a.service.LoadCustomConfig(&driverConfig{}, DriverConfigSection); err != nil {
+		a.log.Warnf("Load the driver config for watching failed: %v", err)
+		return
+	}
+	if err := a.service.ListenForCustomConfigChanges(&map[string]string{}, DriverConfigSection, a.onDriverConfigChanged); err != nil {
+		a.log.Warnf("Watch the driver config failed: %v", err)
+	}
+}
+
+// onDriverConfigChanged re-applies the media config and notifies the driver if it implements ConfigWatcher.
+func (a *Agent) onDriverConfigChanged(rawConfig interface{}) {
+	configs, err := decodeConfigs(rawConfig)
+	if err != nil {
+		a.log.Warnf("Decode the changed driver config failed: %v", err)
+		return
+	}
+
+	a.configMutex.Lock()
+	defer a.configMutex.Unlock()
+	old, _ := a.driverConfigs.Load().(map[string]string)
+	if reflect.DeepEqual(old, configs) {
+		return
+	}
+	a.driverConfigs.Store(configs)
+	a.log.Infof("The driver config is changed")
+
+	if err = media.InitializeConfig(configs, a.service.Name()); err != nil {
+		a.log.Errorf("Re-apply the media config failed, keep the one in use: %v", err)
+	}
+	a.applyErrorLanguage(configs)
+	if a.watcher != nil {
+		if err = a.notifyConfigChanged(copyConfigs(old), copyConfigs(configs)); err != nil {
+			a.log.Errorf("Notify the driver of the changed config failed: %v", err)
+		}
+	}
+}
+
+// applyErrorLanguage switches the language of the messages of errors, the one in use is kept if it's invalid.
+func (a *Agent) applyErrorLanguage(configs map[string]string) {
+	if err := contracts.SetLanguage(configs[ErrorLanguageKey]); err != nil {
+		a.log.Errorf("Apply the language of errors failed, keep '%s' in use: %v", contracts.CurrentLanguage(), err)
+	}
+}
+
+// DriverConfigs returns a copy of the driver configs in use, which are updated once the Driver section changes.
+func (a *Agent) DriverConfigs() map[string]string {
+	configs, ok := a.driverConfigs.Load().(map[string]string)
+	if !ok && a.service != nil {
+		// The configs are loaded from the device service until the Driver section is watched.
+		configs = a.service.DriverConfigs()
+	}
+	return copyConfigs(configs)
+}
+
+// notifyConfigChanged calls the ConfigWatcher with the panic recovered.
+func (a *Agent) notifyConfigChanged(old, new map[string]string) (err error) {
+	defer a.recoverPanic("", "OnConfigChanged", &err)
+	a.watcher.OnConfigChanged(old, new)
+	return nil
+}
+
+// decodeConfigs decodes the Driver section sent by the configuration provider.
+func decodeConfigs(rawConfig interface{}) (map[string]string, error) {
+	switch config := rawConfig.(type) {
+	case *map[string]string:
+		if config == nil {
+			return nil, fmt.Errorf("unexpected nil config")
+		}
+		return copyConfigs(*config), nil
+	case *driverConfig:
+		return copyConfigs(config.Driver), nil
+	default:
+		return cast.ToStringMapStringE(rawConfig)
+	}
+}
+
+func copyConfigs(configs map[string]string) map[string]string {
+	copied := make(map[string]string, len(configs))
+	for k, v := range configs {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+
+	sdkmocks "github.com/edgexfoundry/device-sdk-go/v2/pkg/interfaces/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces/mocks"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/media"
+)
+
+func TestWatchDriverConfig(t *testing.T) {
+	initial := map[string]string{"MediaServer": "http://media-server:80", "MediaSecret": "secret", "Option": "1"}
+	require.NoError(t, media.InitializeConfig(initial, "driver"))
+
+	var callback func(interface{})
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("Name").Return("driver")
+	mockService.On("DriverConfigs").Return(initial)
+	mockService.On("LoadCustomConfig", mock.Anything, DriverConfigSection).Return(nil)
+	mockService.On("ListenForCustomConfigChanges", mock.Anything, DriverConfigSection, mock.Anything).Run(
+		func(args mock.Arguments) {
+			callback = args[2].(func(interface{}))
+		},
+	).Return(nil)
+	// the driver reads the configs in use while notified
+	var seen map[string]string
+	mockWatcher := &mocks.ConfigWatcher{}
+	mockWatcher.On("OnConfigChanged", mock.Anything, mock.Anything).Run(
+		func(mock.Arguments) {
+			seen = DriverConfigs()
+		},
+	).Return()
+
+	a := &Agent{service: mockService, watcher: mockWatcher, log: logger.D}
+	defer func(prev *Agent) { agent = prev }(agent)
+	agent = a
+	require.Equal(t, initial, DriverConfigs())
+	a.watchDriverConfig()
+	require.NotNil(t, callback)
+	require.Equal(t, initial, a.DriverConfigs())
+
+	// nothing is notified if the configs are not changed
+	callback(&map[string]string{"MediaServer": "http://media-server:80", "MediaSecret": "secret", "Option": "1"})
+	mockWatcher.AssertNotCalled(t, "OnConfigChanged", mock.Anything, mock.Anything)
+
+	changed := map[string]string{"MediaServer": "http://new-media-server:80", "MediaSecret": "new", "Option": "2"}
+	callback(&changed)
+	mockWatcher.AssertCalled(t, "OnConfigChanged", initial, changed)
+	require.Equal(t, changed, a.DriverConfigs())
+	require.Equal(t, changed, seen)
+	require.Equal(t, "new-media-server", media.HostName())
+	require.Equal(t, "new", media.Secret())
+
+	// the media config in use is kept if invalid, and the driver is still notified
+	invalid := map[string]interface{}{"MediaServer": "127.0.0.1:80", "Option": "3"}
+	callback(invalid)
+	mockWatcher.AssertNumberOfCalls(t, "OnConfigChanged", 2)
+	require.Equal(t, "new", media.Secret())
+	require.Equal(t, "3", a.DriverConfigs()["Option"])
+
+	// the language of errors is switched, and the one in use is kept if unsupported
+	defer func() { _ = contracts.SetLanguage("") }()
+	callback(&map[string]string{"Option": "4", ErrorLanguageKey: "en"})
+	require.Equal(t, contracts.English, contracts.CurrentLanguage())
+	callback(&map[string]string{"Option": "5", ErrorLanguageKey: "fr"})
+	require.Equal(t, contracts.English, contracts.CurrentLanguage())
+	callback(&map[string]string{"Option": "6"})
+	require.Equal(t, contracts.Chinese, contracts.CurrentLanguage())
+}
+
+func TestNotifyConfigChangedWithPanic(t *testing.T) {
+	mockWatcher := &mocks.ConfigWatcher{}
+	mockWatcher.On("OnConfigChanged", mock.Anything, mock.Anything).Panic("oops")
+
+	a := &Agent{watcher: mockWatcher, log: logger.D}
+	require.Error(t, a.notifyConfigChanged(nil, map[string]string{}))
+	require.EqualValues(t, 1, a.PanicCounts()[""])
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+// SubscribeEvents subscribes the events which have not been subscribed yet, the events already subscribed are skipped.
+func (a *Agent) SubscribeEvents(device *contracts.Device, reqs []contracts.EventRequest) error {
+	if a.subscriber == nil {
+		return errors.NewCommonEdgeX(errors.KindNotImplemented, "Please implement the event subscriber interface", nil)
+	}
+
+	a.eventMutex.Lock()
+	defer a.eventMutex.Unlock()
+
+	if a.events == nil {
+		a.events = make(map[string]map[string]contracts.EventRequest)
+	}
+	subscribed := a.events[device.Name]
+	if subscribed == nil {
+		subscribed = make(map[string]contracts.EventRequest)
+	}
+
+	fresh := make([]contracts.EventRequest, 0, len(reqs))
+	for _, req := range reqs {
+		if _, exist := subscribed[req.Native().DeviceResourceName]; exist {
+			req.Skip()
+			continue
+		}
+		fresh = append(fresh, req)
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if err := a.subscribeEvent(device, fresh); err != nil {
+		return err
+	}
+	for _, req := range fresh {
+		if req.Error() == nil && !req.Skipped() {
+			subscribed[req.Native().DeviceResourceName] = req
+		}
+	}
+	a.events[device.Name] = subscribed
+	a.log.Infof("device '%s' has subscribed %d events", device.Name, len(subscribed))
+	return nil
+}
+
+// UnsubscribeEvents unsubscribes all the events subscribed by the device.
+func (a *Agent) UnsubscribeEvents(device *contracts.Device) error {
+	if a.subscriber == nil {
+		return nil
+	}
+
+	a.eventMutex.Lock()
+	defer a.eventMutex.Unlock()
+
+	subscribed := a.events[device.Name]
+	if len(subscribed) == 0 {
+		return nil
+	}
+	delete(a.events, device.Name)
+
+	reqs := make([]contracts.EventRequest, 0, len(subscribed))
+	for _, req := range subscribed {
+		reqs = append(reqs, req)
+	}
+	a.log.Infof("device '%s' unsubscribes %d events", device.Name, len(reqs))
+	return a.unsubscribeEvent(device, reqs)
+}
+
+func (a *Agent) subscribeEvent(device *contracts.Device, reqs []contracts.EventRequest) (err error) {
+	defer recoverRequests(a, device.Name, "SubscribeEvent", reqs, &err)
+	return a.subscriber.SubscribeEvent(device, reqs)
+}
+
+func (a *Agent) unsubscribeEvent(device *contracts.Device, reqs []contracts.EventRequest) (err error) {
+	defer a.recoverPanic(device.Name, "UnsubscribeEvent", &err)
+	return a.subscriber.UnsubscribeEvent(device, reqs)
+}
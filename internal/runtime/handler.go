@@ -18,49 +18,90 @@ package runtime
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 
 	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
 )
 
 func (a *Agent) HandleReadCommands(deviceName string, protocols map[string]models.ProtocolProperties,
 	reqs []sdkmodels.CommandRequest) ([]*sdkmodels.CommandValue, error) {
 
 	responses := make([]*sdkmodels.CommandValue, 0)
-	readRequests, callRequests, err := GroupRequestByCategory(reqs)
+	readRequests, callRequests, eventRequests, err := GroupRequestByCategory(reqs)
 	if err != nil {
 		a.log.Errorf("group requests by category failed: %v", err)
 		return nil, err
 	}
+	profile := a.profileName(deviceName)
+	bindProfile(profile, readRequests)
+	bindProfile(profile, callRequests)
+	bindProfile(profile, eventRequests)
 
-	device := contracts.WrapDevice(deviceName, protocols)
+	// The reads of the same resources in flight are joined, and the cached results are used if not expired.
+	flight := a.cache.Begin(deviceName, readRequests, a.cacheTTL)
+	defer flight.Complete(nil)
+	if err = flight.Wait(a.context()); err != nil {
+		flight.Complete(err)
+		return nil, err
+	}
 
-	if len(readRequests) > 0 {
-		if err = a.driver.ReadProperty(device, readRequests); err != nil {
+	if len(flight.Misses()) > 0 || len(callRequests) > 0 || len(eventRequests) > 0 {
+		release, err := a.acquire(deviceName)
+		if err != nil {
+			flight.Complete(err)
+			return nil, err
+		}
+		defer release()
+	}
+
+	device := a.wrapDevice(deviceName, protocols)
+
+	if misses := flight.Misses(); len(misses) > 0 {
+		err = a.readProperty(device, misses)
+		if err == nil {
+			a.transformResults(deviceName, misses)
+		}
+		flight.Complete(err)
+		if err != nil {
 			a.PostProcessDevice(device, err)
 			a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
-			return nil, err
+			return nil, contracts.ToEdgeX(err)
 		}
+	}
+	if len(readRequests) > 0 {
 		if err = a.PostProcessRequests(deviceName, readRequests, false, &responses); err != nil {
 			return responses, err
 		}
+		responses = a.filterAutoEvents(deviceName, responses)
 	}
 	if len(callRequests) > 0 {
-		if err = a.driver.CallService(device, callRequests); err != nil {
+		if err = a.callService(device, callRequests); err != nil {
 			a.PostProcessDevice(device, err)
 			a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
-			return nil, err
+			return nil, contracts.ToEdgeX(err)
 		}
 		if err = a.PostProcessRequests(deviceName, callRequests, false, &responses); err != nil {
 			return responses, err
 		}
 	}
-
+	if len(eventRequests) > 0 {
+		if err = a.SubscribeEvents(device, eventRequests); err != nil {
+			a.PostProcessDevice(device, err)
+			a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
+			return nil, contracts.ToEdgeX(err)
+		}
+		if err = a.PostProcessRequests(deviceName, eventRequests, false, &responses); err != nil {
+			return responses, err
+		}
+	}
 	return responses, nil
 }
 
@@ -74,26 +115,67 @@ func (a *Agent) HandleWriteCommands(deviceName string, protocols map[string]mode
 		return errors.NewCommonEdgeX(errors.KindServerError, "the length of requests and params is not match", nil)
 	}
 
-	device := contracts.WrapDevice(deviceName, protocols)
+	release, err := a.acquire(deviceName)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	device := a.wrapDevice(deviceName, protocols)
 	requests := make([]contracts.WriteRequest, len(reqs))
+	valid := make([]contracts.WriteRequest, 0, len(reqs))
 	for i := 0; i < len(reqs); i++ {
 		requests[i] = contracts.NewWriteRequest(reqs[i], params[i])
+		if err = a.validateWrite(deviceName, requests[i]); err != nil {
+			a.log.Warnf("reject the write of '%s' to device '%s': %v", reqs[i].DeviceResourceName, deviceName, err)
+			requests[i].Failed(err)
+			continue
+		}
+		// The engineering value validated is transformed back into the raw value before written by driver.
+		if requests[i], err = a.transformParam(deviceName, requests[i]); err != nil {
+			a.log.Warnf("reject the write of '%s' to device '%s': %v", reqs[i].DeviceResourceName, deviceName, err)
+			requests[i].Failed(err)
+			continue
+		}
+		valid = append(valid, requests[i])
 	}
 
-	if err = a.driver.WriteProperty(device, requests); err != nil {
-		a.PostProcessDevice(device, err)
-		a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
-		return err
+	if len(valid) > 0 {
+		bindProfile(a.profileName(deviceName), valid)
+		err = a.writeProperty(device, valid)
+		// The cached reads of the written resources are stale, even if the driver failed halfway.
+		a.cache.Evict(deviceName, resourceNames(valid)...)
+		if err != nil {
+			a.PostProcessDevice(device, err)
+			a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
+			return contracts.ToEdgeX(err)
+		}
 	}
 
 	return a.PostProcessRequests(deviceName, requests, true, nil)
 }
 
+// resourceNames returns the names of the resources requested.
+func resourceNames[T contracts.BaseRequest](reqs []T) []string {
+	names := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		names = append(names, req.Native().DeviceResourceName)
+	}
+	return names
+}
+
+// PostProcessRequests collects the results of the requests into cvs and updates the statistics of the device.
+// In strict mode or for writes, a BatchError listing the outcome of each request is returned if any request
+// failed, and the results of the successful ones are still collected. The results which cannot be converted into
+// command values fail as DataParseError in any mode, rather than left out of the event silently. The BatchError is
+// wrapped by ToEdgeX with the codes of the failed requests in the message, since the device service responds 500
+// to core-command regardless of the status code of the EdgeX error.
 func (a *Agent) PostProcessRequests(deviceName string, reqs interface{}, write bool, cvs *[]*sdkmodels.CommandValue) error {
 	rValue := reflect.ValueOf(reqs)
 	if rValue.Kind() != reflect.Slice {
 		return errors.NewCommonEdgeX(errors.KindContractInvalid, "requests to be processed not a slice", nil)
 	}
+	batch, parseFailed := &contracts.BatchError{}, false
 	for i := 0; i < rValue.Len(); i++ {
 		req, ok := rValue.Index(i).Interface().(contracts.BaseRequest)
 		if !ok {
@@ -102,69 +184,294 @@ func (a *Agent) PostProcessRequests(deviceName string, reqs interface{}, write b
 		if req.Skipped() || !write && req.Error() == nil && req.Result() == nil {
 			continue
 		}
+		resourceName := req.Native().DeviceResourceName
 		if err := req.Error(); err != nil {
 			a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
-			if a.StrictMode || write {
-				return err
-			}
+			batch.Add(resourceName, err)
 			continue
 		}
 		if result := req.Result(); result != nil && cvs != nil {
-			cv, err := result.CommandValue(req.Native().DeviceResourceName, req.Native().Type)
+			cv, err := result.CommandValue(resourceName, req.Native().Type)
 			if err != nil {
-				return err
+				a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
+				batch.Add(resourceName, contracts.NewError(contracts.DataParseError, err))
+				parseFailed = true
+				continue
 			}
 			*cvs = append(*cvs, cv)
 		}
+		batch.Add(resourceName, nil)
 		a.StatusManager.OnHandleCommandsSuccessfully(deviceName, 1)
 	}
+	if a.StrictMode || write || parseFailed {
+		return contracts.ToEdgeX(batch.Err())
+	}
 	return nil
 }
 
 func (a *Agent) ReportEvent(event *contracts.AsyncValues) error {
-	a.async <- event
+	return a.ReportEventContext(a.context(), event)
+}
+
+func (a *Agent) ReportEventContext(ctx context.Context, event *contracts.AsyncValues) error {
+	if event = a.aggregateValues(event); event == nil {
+		return nil
+	}
+	attributes := a.resourceAttributes(event.DeviceName)
+	// The readings are recorded before the following ones of the device are checked, so that the equal readings
+	// reported concurrently are not delivered twice.
+	defer a.filter.Lock(event.DeviceName)()
+	if values := a.filterReadings(event.DeviceName, event.CommandValues, attributes); len(values) < len(event.CommandValues) {
+		if len(values) == 0 {
+			return nil
+		}
+		event = &contracts.AsyncValues{DeviceName: event.DeviceName, SourceName: event.SourceName, CommandValues: values}
+	}
+	if err := a.buffer.Put(ctx, event); err != nil {
+		a.log.Warnf("drop the event of device '%s', policy: %s, error: %v", event.DeviceName, a.buffer.Policy(), err)
+		return err
+	}
+	// The readings dropped by the buffer are not recorded, so that the following equal ones are still reported.
+	a.recordReadings(event.DeviceName, event.CommandValues, attributes)
 	return nil
 }
 
+// HandleAsyncResults forwards the async values reported by driver to the device service. The values sent to
+// the async channel of driver are put into the buffer first, so that the overflow policy also applies to them.
 func (a *Agent) HandleAsyncResults(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	defer func() {
 		wg.Done()
 	}()
 
+	go a.bufferAsyncValues(ctx, wg)
+	if a.spool != nil {
+		go a.replaySpool(ctx, wg)
+	}
+	if a.aggregator != nil {
+		go a.flushAggregates(ctx, wg)
+	}
 	for {
-		select {
-		case <-ctx.Done():
+		result, err := a.buffer.Get(ctx)
+		if err != nil {
 			a.log.Infof("Stop handle async results...")
 			return
-		case result := <-a.async:
+		}
+		spooled, err := a.deliverValues(ctx, result)
+		if err != nil {
+			a.log.Warnf("report values of device '%s' failed: %v", result.DeviceName, err)
+			continue
+		}
+		if !spooled {
 			a.StatusManager.OnHandleCommandsSuccessfully(result.DeviceName, int64(len(result.CommandValues)))
-			a.asyncCh <- result.Transform()
 		}
 	}
 }
 
-func (a *Agent) AddDevice(deviceName string, protocols map[string]models.ProtocolProperties, _ models.AdminState) error {
-	a.log.Infof("device '%s' is added", deviceName)
+func (a *Agent) bufferAsyncValues(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	defer wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case values := <-a.async:
+			_ = a.ReportEventContext(ctx, values)
+		}
+	}
+}
+
+// validateWrite checks the parameter of the write request against the resource defined in the device profile,
+// the request is not validated if the resource is unknown or the validation is skipped.
+func (a *Agent) validateWrite(deviceName string, req contracts.WriteRequest) error {
+	if a.SkipWriteValidation || a.service == nil {
+		return nil
+	}
+	resource, ok := a.service.DeviceResource(deviceName, req.Native().DeviceResourceName)
+	if !ok {
+		return nil
+	}
+	// The parameter is still the engineering value if the properties are transformed by the agent rather than the
+	// device service, so its range is checked regardless of the scale, offset and base.
+	if a.TransformProperties {
+		resource.Properties.Scale, resource.Properties.Offset, resource.Properties.Base = "", "", ""
+	}
+	return contracts.ValidateWriteParameter(resource, req.Param())
+}
+
+// filterReadings drops the async readings which are not changed enough since the last reported ones, according to
+// the filter defined in the attributes of their resources. The readings of commands are only filtered by
+// filterAutoEvents, so that the current values are returned to the callers otherwise.
+func (a *Agent) filterReadings(deviceName string, cvs []*sdkmodels.CommandValue, attributes func(string) map[string]interface{}) []*sdkmodels.CommandValue {
+	if a.filter == nil || len(cvs) == 0 {
+		return cvs
+	}
+	now := time.Now()
+	filtered := make([]*sdkmodels.CommandValue, 0, len(cvs))
+	for _, cv := range cvs {
+		if cv != nil && !a.filter.Check(deviceName, cv, attributes(cv.DeviceResourceName), now) {
+			a.log.Debugf("the reading of '%s' from device '%s' is filtered", cv.DeviceResourceName, deviceName)
+			a.filteredCounter.Inc(1)
+			continue
+		}
+		filtered = append(filtered, cv)
+	}
+	return filtered
+}
+
+// filterAutoEvents drops the read results of the resources polled by the AutoEvents of device which are not changed
+// enough since the last reported ones, if FilterAutoEvents is enabled. The reads of these resources by core-command
+// cannot be told from the AutoEvents, so they are filtered as well.
+func (a *Agent) filterAutoEvents(deviceName string, cvs []*sdkmodels.CommandValue) []*sdkmodels.CommandValue {
+	if !a.FilterAutoEvents || a.filter == nil || len(cvs) == 0 {
+		return cvs
+	}
+	polled := a.autoEventResources(deviceName)
+	if len(polled) == 0 {
+		return cvs
+	}
+	attributes := a.resourceAttributes(deviceName)
+	defer a.filter.Lock(deviceName)()
+	now := time.Now()
+	filtered := make([]*sdkmodels.CommandValue, 0, len(cvs))
+	for _, cv := range cvs {
+		if cv != nil && polled[cv.DeviceResourceName] && !a.filter.Allow(deviceName, cv, attributes(cv.DeviceResourceName), now) {
+			a.log.Debugf("the reading of '%s' from device '%s' is filtered", cv.DeviceResourceName, deviceName)
+			a.filteredCounter.Inc(1)
+			continue
+		}
+		filtered = append(filtered, cv)
+	}
+	return filtered
+}
+
+// autoEventResources returns the resources read by the AutoEvents of device, the sources of AutoEvents are either
+// device resources or device commands.
+func (a *Agent) autoEventResources(deviceName string) map[string]bool {
+	if a.service == nil {
+		return nil
+	}
+	device, err := a.service.GetDeviceByName(deviceName)
+	if err != nil {
+		return nil
+	}
+	resources := make(map[string]bool)
+	for _, event := range device.AutoEvents {
+		command, ok := a.service.DeviceCommand(deviceName, event.SourceName)
+		if !ok {
+			resources[event.SourceName] = true
+			continue
+		}
+		for _, operation := range command.ResourceOperations {
+			resources[operation.DeviceResource] = true
+		}
+	}
+	return resources
+}
+
+// recordReadings records the readings accepted for delivery as the last reported ones.
+func (a *Agent) recordReadings(deviceName string, cvs []*sdkmodels.CommandValue, attributes func(string) map[string]interface{}) {
+	if a.filter == nil {
+		return
+	}
+	now := time.Now()
+	for _, cv := range cvs {
+		if cv != nil {
+			a.filter.Record(deviceName, cv, attributes(cv.DeviceResourceName), now)
+		}
+	}
+}
+
+// resourceAttributes returns the function looking up the attributes of the resources of device in the device profile.
+func (a *Agent) resourceAttributes(deviceName string) func(string) map[string]interface{} {
+	return func(resourceName string) map[string]interface{} {
+		if a.service == nil {
+			return nil
+		}
+		resource, ok := a.service.DeviceResource(deviceName, resourceName)
+		if !ok {
+			return nil
+		}
+		return resource.Attributes
+	}
+}
+
+// cacheTTL returns the TTL of the cached result for the read request, the attribute 'cacheTTL' takes precedence
+// over the ReadCacheTTL of agent, and zero disables the cache for the resource.
+func (a *Agent) cacheTTL(req contracts.ReadRequest) time.Duration {
+	if attributes := req.Attributes(); attributes != nil && attributes[contracts.CacheTTLKey] != nil {
+		ttl, err := utils.CastDuration(attributes[contracts.CacheTTLKey])
+		if err != nil {
+			return a.ReadCacheTTL
+		}
+		return ttl
+	}
+	return a.ReadCacheTTL
+}
+
+func (a *Agent) AddDevice(deviceName string, protocols map[string]models.ProtocolProperties, adminState models.AdminState) error {
+	a.log.Infof("device '%s' is added with admin state '%s'", deviceName, adminState)
+	a.setAdminState(deviceName, adminState)
+	device := a.wrapDevice(deviceName, protocols)
+	if device.IsLocked() {
+		// The locked device is not added to the driver until it is unlocked, so that no connections are opened.
+		a.deferDevice(deviceName)
+		a.scheduler.Unschedule(deviceName)
+		a.handleAdminState(device)
+		return nil
+	}
 	a.StatusManager.OnAddDevice(deviceName)
+	// The device is polled after the driver handled it.
+	defer a.schedulePolling(deviceName)
 	if a.handler == nil {
 		return nil
 	}
 	// Call the interface 'AddDevice' if the driver has implemented the handler.
-	device := contracts.WrapDevice(deviceName, protocols)
-	err := a.handler.AddDevice(device)
+	err := a.handleDevice(device, "AddDevice", a.handler.AddDevice)
 	a.PostProcessDevice(device, err)
 	return err
 }
 
-func (a *Agent) UpdateDevice(deviceName string, protocols map[string]models.ProtocolProperties, _ models.AdminState) error {
-	a.log.Infof("device '%s' is updated", deviceName)
+func (a *Agent) UpdateDevice(deviceName string, protocols map[string]models.ProtocolProperties, adminState models.AdminState) error {
+	a.log.Infof("device '%s' is updated with admin state '%s'", deviceName, adminState)
+	changed := a.setAdminState(deviceName, adminState)
+	device := a.wrapDevice(deviceName, protocols)
+	if changed {
+		// The status of the locked device is not managed until it is unlocked.
+		if device.IsLocked() {
+			a.StatusManager.OnRemoveDevice(deviceName)
+		} else {
+			a.StatusManager.OnAddDevice(deviceName)
+		}
+		defer a.handleAdminState(device)
+	}
+	a.cache.Purge(deviceName)
+	a.filter.Purge(deviceName)
+	a.aggregator.Purge(deviceName)
+	contracts.PurgeAttributes(a.profileName(deviceName))
+	// The device is polled again with the updated profile after the driver handled it.
+	defer a.schedulePolling(deviceName)
+	// The subscribed events will be subscribed again with the updated device.
+	if err := a.UnsubscribeEvents(device); err != nil {
+		a.log.Warnf("unsubscribe events of device '%s' failed: %v", deviceName, err)
+	}
 	if a.handler == nil {
+		a.forgetDeferred(deviceName)
 		return nil
 	}
+	if a.isDeferred(deviceName) {
+		if device.IsLocked() {
+			return nil
+		}
+		// The device locked when added is added to the driver once it is unlocked.
+		a.forgetDeferred(deviceName)
+		err := a.handleDevice(device, "AddDevice", a.handler.AddDevice)
+		a.PostProcessDevice(device, err)
+		return err
+	}
 	// Call the interface 'UpdateDevice' if the driver has implemented the handler.
-	device := contracts.WrapDevice(deviceName, protocols)
-	err := a.handler.UpdateDevice(device)
+	err := a.handleDevice(device, "UpdateDevice", a.handler.UpdateDevice)
 	a.PostProcessDevice(device, err)
 	return err
 }
@@ -172,12 +479,53 @@ func (a *Agent) UpdateDevice(deviceName string, protocols map[string]models.Prot
 func (a *Agent) RemoveDevice(deviceName string, protocols map[string]models.ProtocolProperties) error {
 	a.log.Infof("device '%s' is removed", deviceName)
 	a.StatusManager.OnRemoveDevice(deviceName)
-	if a.handler == nil {
+	a.scheduler.Unschedule(deviceName)
+	a.cache.Purge(deviceName)
+	a.filter.Purge(deviceName)
+	a.aggregator.Purge(deviceName)
+	device := a.wrapDevice(deviceName, protocols)
+	// The admin state of the removed device is forgotten.
+	a.setAdminState(deviceName, models.Unlocked)
+	deferred := a.forgetDeferred(deviceName)
+	if err := a.UnsubscribeEvents(device); err != nil {
+		a.log.Warnf("unsubscribe events of device '%s' failed: %v", deviceName, err)
+	}
+	// The device never added to the driver is not removed from it.
+	if a.handler == nil || deferred {
 		return nil
 	}
 	// Call the interface 'RemoveDevice' if the driver has implemented the handler.
-	device := contracts.WrapDevice(deviceName, protocols)
-	return a.handler.RemoveDevice(device)
+	return a.handleDevice(device, "RemoveDevice", a.handler.RemoveDevice)
+}
+
+// handleDevice calls the callback of DeviceHandler with the panic recovered.
+func (a *Agent) handleDevice(device *contracts.Device, operation string, callback func(*contracts.Device) error) (err error) {
+	defer a.recoverPanic(device.Name, operation, &err)
+	return callback(device)
+}
+
+// acquire waits for the turn of the device to handle commands, the returned function must be called to release the turn.
+func (a *Agent) acquire(deviceName string) (func(), error) {
+	if a.dispatcher == nil {
+		return func() {}, nil
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if a.QueueTimeout > 0 {
+		ctx, cancel = context.WithTimeout(a.context(), a.QueueTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(a.context())
+	}
+	defer cancel()
+
+	release, err := a.dispatcher.Acquire(ctx, deviceName)
+	if err != nil {
+		a.log.Warnf("device '%s' waits for handling commands failed, %d commands are waiting: %v",
+			deviceName, a.dispatcher.DeviceDepth(deviceName), err)
+		return nil, errors.NewCommonEdgeX(errors.KindServiceUnavailable, fmt.Sprintf("device '%s' is too busy to handle commands", deviceName), err)
+	}
+	return release, nil
 }
 
 func (a *Agent) PostProcessDevice(device *contracts.Device, err error) {
@@ -188,10 +536,11 @@ func (a *Agent) PostProcessDevice(device *contracts.Device, err error) {
 	a.StatusManager.UpdateDeviceStatus(device.Name, string(device.OperatingState), device.Message)
 }
 
-func GroupRequestByCategory(reqs []sdkmodels.CommandRequest) ([]contracts.ReadRequest, []contracts.CallRequest, error) {
+func GroupRequestByCategory(reqs []sdkmodels.CommandRequest) ([]contracts.ReadRequest, []contracts.CallRequest, []contracts.EventRequest, error) {
 	var (
-		read []contracts.ReadRequest
-		call []contracts.CallRequest
+		read  []contracts.ReadRequest
+		call  []contracts.CallRequest
+		event []contracts.EventRequest
 	)
 
 	for _, req := range reqs {
@@ -202,11 +551,14 @@ func GroupRequestByCategory(reqs []sdkmodels.CommandRequest) ([]contracts.ReadRe
 		case contracts.Service:
 			request, err := contracts.NewCallRequest(req)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 			call = append(call, request)
+		case contracts.Event:
+			request := contracts.NewEventRequest(req)
+			event = append(event, request)
 		}
 	}
 
-	return read, call, nil
+	return read, call, event, nil
 }
@@ -19,15 +19,26 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	sdkmocks "github.com/edgexfoundry/device-sdk-go/v2/pkg/interfaces/mocks"
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+	edgexmodels "github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/rcrowley/go-metrics"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/volcengine/vei-driver-sdk-go/internal/aggregate"
+	"github.com/volcengine/vei-driver-sdk-go/internal/buffer"
+	"github.com/volcengine/vei-driver-sdk-go/internal/cache"
+	"github.com/volcengine/vei-driver-sdk-go/internal/dispatcher"
+	"github.com/volcengine/vei-driver-sdk-go/internal/filter"
 	"github.com/volcengine/vei-driver-sdk-go/internal/status"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces"
@@ -94,6 +105,7 @@ func TestAsyncReport(t *testing.T) {
 		stop:          cancel,
 		wg:            &sync.WaitGroup{},
 		async:         make(chan *contracts.AsyncValues, 1),
+		buffer:        buffer.New(1, contracts.Block, 0),
 		StatusManager: MockStatusManager(nil),
 	}
 
@@ -164,6 +176,133 @@ func TestDeviceLifeCycleWithHandler(t *testing.T) {
 	require.Nil(t, devices[deviceName])
 }
 
+func TestDeviceLifeCycleWithAdminState(t *testing.T) {
+	mockHandler := &mocks.DeviceHandler{}
+	mockHandler.On("AddDevice", mock.Anything).Return(nil)
+	mockHandler.On("UpdateDevice", mock.Anything).Return(nil)
+	mockHandler.On("RemoveDevice", mock.Anything).Return(nil)
+	states := make([]contracts.AdminState, 0)
+	record := func(args mock.Arguments) {
+		states = append(states, args[0].(*contracts.Device).AdminState)
+	}
+	mockAdminHandler := &mocks.AdminStateHandler{}
+	mockAdminHandler.On("OnLocked", mock.Anything).Run(record).Return(nil)
+	mockAdminHandler.On("OnUnlocked", mock.Anything).Run(record).Return(fmt.Errorf("connect failed"))
+
+	devices := make(map[string]*contracts.Device, 0)
+	a := &Agent{handler: mockHandler, admin: mockAdminHandler, log: logger.D}
+	a.StatusManager = status.Exclude(MockStatusManager(devices), a.isLocked)
+
+	deviceName := "device-001"
+	require.NoError(t, a.AddDevice(deviceName, nil, edgexmodels.Locked))
+	require.True(t, a.isLocked(deviceName))
+	require.Nil(t, devices[deviceName])
+	require.Equal(t, []contracts.AdminState{contracts.LOCKED}, states)
+	// The locked device is not added to the driver, so no connections are opened.
+	mockHandler.AssertNotCalled(t, "AddDevice", mock.Anything)
+
+	// The status of the locked device is ignored.
+	a.StatusManager.UpdateDeviceStatus(deviceName, string(contracts.UP), "")
+	require.Nil(t, devices[deviceName])
+
+	// The updates of the locked device are not passed to the driver either.
+	require.NoError(t, a.UpdateDevice(deviceName, nil, edgexmodels.Locked))
+	mockHandler.AssertNotCalled(t, "UpdateDevice", mock.Anything)
+
+	// The device is added to the driver once unlocked.
+	require.NoError(t, a.UpdateDevice(deviceName, nil, edgexmodels.Unlocked))
+	require.False(t, a.isLocked(deviceName))
+	require.NotNil(t, devices[deviceName])
+	require.Equal(t, []contracts.AdminState{contracts.LOCKED, contracts.UNLOCKED}, states)
+	mockHandler.AssertNumberOfCalls(t, "AddDevice", 1)
+	mockHandler.AssertNotCalled(t, "UpdateDevice", mock.Anything)
+
+	// The callbacks are invoked only when the admin state changes.
+	require.NoError(t, a.UpdateDevice(deviceName, nil, edgexmodels.Unlocked))
+	require.Len(t, states, 2)
+	mockHandler.AssertNumberOfCalls(t, "UpdateDevice", 1)
+
+	require.NoError(t, a.UpdateDevice(deviceName, nil, edgexmodels.Locked))
+	require.Nil(t, devices[deviceName])
+	require.Equal(t, []contracts.AdminState{contracts.LOCKED, contracts.UNLOCKED, contracts.LOCKED}, states)
+
+	require.NoError(t, a.RemoveDevice(deviceName, nil))
+	require.False(t, a.isLocked(deviceName))
+	require.Len(t, states, 3)
+	mockHandler.AssertNumberOfCalls(t, "RemoveDevice", 1)
+
+	// The device removed while it's locked since added is never passed to the driver.
+	require.NoError(t, a.AddDevice("device-002", nil, edgexmodels.Locked))
+	require.NoError(t, a.RemoveDevice("device-002", nil))
+	mockHandler.AssertNumberOfCalls(t, "AddDevice", 1)
+	mockHandler.AssertNumberOfCalls(t, "RemoveDevice", 1)
+}
+
+func TestHandleLockedDevices(t *testing.T) {
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("Devices").Return([]edgexmodels.Device{
+		{Name: "device-001", AdminState: edgexmodels.Locked},
+		{Name: "device-002", AdminState: edgexmodels.Unlocked},
+	})
+	mockAdminHandler := &mocks.AdminStateHandler{}
+	mockAdminHandler.On("OnLocked", mock.Anything).Return(nil)
+
+	a := &Agent{service: mockService, admin: mockAdminHandler, log: logger.D}
+	for _, device := range mockService.Devices() {
+		a.setAdminState(device.Name, device.AdminState)
+	}
+	a.handleLockedDevices()
+	mockAdminHandler.AssertNumberOfCalls(t, "OnLocked", 1)
+	mockAdminHandler.AssertCalled(t, "OnLocked", mock.MatchedBy(func(device *contracts.Device) bool {
+		return device.Name == "device-001" && device.IsLocked()
+	}))
+}
+
+func TestHandleEventCommands(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockSubscriber := &mocks.EventSubscriber{}
+	mockSubscriber.On("SubscribeEvent", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			for _, req := range args[1].([]contracts.EventRequest) {
+				req.SetResult(contracts.NewSimpleResult(map[string]interface{}{"level": 1}))
+			}
+		},
+	).Return(nil)
+	mockSubscriber.On("UnsubscribeEvent", mock.Anything, mock.Anything).Return(nil)
+
+	deviceName := "device-001"
+	reqs := []models.CommandRequest{{
+		DeviceResourceName: "security:alarm",
+		Type:               common.ValueTypeObject,
+		Attributes:         map[string]interface{}{contracts.CategoryKey: contracts.Event},
+	}}
+
+	// without subscriber
+	a := &Agent{driver: mockDriver, StatusManager: MockStatusManager(nil), log: logger.D}
+	_, err := a.HandleReadCommands(deviceName, nil, reqs)
+	require.Error(t, err)
+
+	// subscribe for the first time
+	a.subscriber = mockSubscriber
+	responses, err := a.HandleReadCommands(deviceName, nil, reqs)
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	require.Len(t, a.events[deviceName], 1)
+
+	// already subscribed
+	responses, err = a.HandleReadCommands(deviceName, nil, reqs)
+	require.NoError(t, err)
+	require.Len(t, responses, 0)
+	mockSubscriber.AssertNumberOfCalls(t, "SubscribeEvent", 1)
+
+	// unsubscribe when the device is removed
+	err = a.RemoveDevice(deviceName, nil)
+	require.NoError(t, err)
+	require.Len(t, a.events[deviceName], 0)
+	mockSubscriber.AssertNumberOfCalls(t, "UnsubscribeEvent", 1)
+	mockDriver.AssertNotCalled(t, "ReadProperty", mock.Anything, mock.Anything)
+}
+
 func BenchmarkGroupRequestByCategory10(b *testing.B) {
 	length := 10
 	reqs := make([]models.CommandRequest, 0, length)
@@ -176,7 +315,7 @@ func BenchmarkGroupRequestByCategory10(b *testing.B) {
 	}
 
 	for n := 0; n < b.N; n++ {
-		read, call, err := GroupRequestByCategory(reqs)
+		read, call, _, err := GroupRequestByCategory(reqs)
 		require.NoError(b, err)
 		require.Equal(b, int(float32(length)*0.9), len(read))
 		require.Equal(b, int(float32(length)*0.1), len(call))
@@ -195,9 +334,533 @@ func BenchmarkGroupRequestByCategory100(b *testing.B) {
 	}
 
 	for n := 0; n < b.N; n++ {
-		read, call, err := GroupRequestByCategory(reqs)
+		read, call, _, err := GroupRequestByCategory(reqs)
 		require.NoError(b, err)
 		require.Equal(b, int(float32(length)*0.9), len(read))
 		require.Equal(b, int(float32(length)*0.1), len(call))
 	}
 }
+
+func TestHandleCommandsSerially(t *testing.T) {
+	var running, maxRunning int32
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			n := atomic.AddInt32(&running, 1)
+			if n > atomic.LoadInt32(&maxRunning) {
+				atomic.StoreInt32(&maxRunning, n)
+			}
+			time.Sleep(time.Millisecond * 20)
+			atomic.AddInt32(&running, -1)
+		},
+	).Return(nil)
+
+	a := &Agent{
+		driver:        mockDriver,
+		dispatcher:    dispatcher.NewDispatcher(4),
+		StatusManager: MockStatusManager(nil),
+		log:           logger.D,
+	}
+	reqs := []models.CommandRequest{{DeviceResourceName: "temperature", Type: common.ValueTypeFloat32}}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := a.HandleReadCommands("device-001", nil, reqs)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), maxRunning)
+
+	// the queue timeout exceeds
+	a.QueueTimeout = time.Millisecond * 10
+	release, err := a.acquire("device-001")
+	require.NoError(t, err)
+	_, err = a.HandleReadCommands("device-001", nil, reqs)
+	require.Error(t, err)
+	err = a.HandleWriteCommands("device-001", nil, reqs, []*models.CommandValue{{}})
+	require.Error(t, err)
+	release()
+}
+
+func TestHandleReadCommandsWithCache(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			for _, req := range args[1].([]contracts.ReadRequest) {
+				req.SetResult(contracts.NewSimpleResult(int32(1)))
+			}
+		},
+	).Return(nil)
+
+	a := &Agent{
+		driver:        mockDriver,
+		cache:         cache.New(),
+		StatusManager: MockStatusManager(nil),
+		log:           logger.D,
+		ReadCacheTTL:  time.Minute,
+	}
+	reqs := []models.CommandRequest{
+		{DeviceResourceName: "temperature", Type: common.ValueTypeInt32},
+		{DeviceResourceName: "humidity", Type: common.ValueTypeInt32, Attributes: map[string]interface{}{contracts.CacheTTLKey: 0}},
+	}
+
+	for i := 0; i < 3; i++ {
+		responses, err := a.HandleReadCommands("device-001", nil, reqs)
+		require.NoError(t, err)
+		require.Len(t, responses, 2)
+	}
+	mockDriver.AssertNumberOfCalls(t, "ReadProperty", 3)
+	for _, call := range mockDriver.Calls[1:] {
+		require.Len(t, call.Arguments[1], 1)
+	}
+
+	// all hit, the driver is not invoked
+	responses, err := a.HandleReadCommands("device-001", nil, reqs[:1])
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	mockDriver.AssertNumberOfCalls(t, "ReadProperty", 3)
+
+	// purged when the device is updated
+	err = a.UpdateDevice("device-001", nil, "")
+	require.NoError(t, err)
+	_, err = a.HandleReadCommands("device-001", nil, reqs[:1])
+	require.NoError(t, err)
+	mockDriver.AssertNumberOfCalls(t, "ReadProperty", 4)
+
+	// the written resource is read again rather than served from the cache
+	mockDriver.On("WriteProperty", mock.Anything, mock.Anything).Return(nil)
+	param, _ := models.NewCommandValue("temperature", common.ValueTypeInt32, int32(2))
+	err = a.HandleWriteCommands("device-001", nil, reqs[:1], []*models.CommandValue{param})
+	require.NoError(t, err)
+	_, err = a.HandleReadCommands("device-001", nil, reqs[:1])
+	require.NoError(t, err)
+	mockDriver.AssertNumberOfCalls(t, "ReadProperty", 5)
+}
+
+func TestHandleReadCommandsWithFilter(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			for _, req := range args[1].([]contracts.ReadRequest) {
+				req.SetResult(contracts.NewSimpleResult(int32(20)))
+			}
+		},
+	).Return(nil)
+
+	a := &Agent{
+		driver:          mockDriver,
+		filter:          filter.New(),
+		filteredCounter: metrics.NewCounter(),
+		StatusManager:   MockStatusManager(nil),
+		log:             logger.D,
+	}
+	reqs := []models.CommandRequest{
+		{DeviceResourceName: "temperature", Type: common.ValueTypeInt32, Attributes: map[string]interface{}{contracts.DeadbandKey: 1}},
+		{DeviceResourceName: "humidity", Type: common.ValueTypeInt32},
+	}
+
+	// the current values are always returned to the callers of commands, even if unchanged
+	for i := 0; i < 3; i++ {
+		responses, err := a.HandleReadCommands("device-001", nil, reqs)
+		require.NoError(t, err)
+		require.Len(t, responses, 2)
+	}
+	require.EqualValues(t, 0, a.filteredCounter.Count())
+}
+
+func TestHandleReadCommandsWithAutoEventFilter(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			for _, req := range args[1].([]contracts.ReadRequest) {
+				req.SetResult(contracts.NewSimpleResult(int32(20)))
+			}
+		},
+	).Return(nil)
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("GetDeviceByName", "device-001").Return(edgexmodels.Device{
+		Name:        "device-001",
+		ProfileName: "profile",
+		AutoEvents: []edgexmodels.AutoEvent{
+			{SourceName: "environment", Interval: "1s"},
+			{SourceName: "voltage", Interval: "1s"},
+		},
+	}, nil)
+	mockService.On("DeviceCommand", "device-001", "environment").Return(edgexmodels.DeviceCommand{
+		Name:               "environment",
+		ResourceOperations: []edgexmodels.ResourceOperation{{DeviceResource: "temperature"}},
+	}, true)
+	mockService.On("DeviceCommand", "device-001", "voltage").Return(edgexmodels.DeviceCommand{}, false)
+	mockService.On("DeviceResource", "device-001", mock.Anything).Return(edgexmodels.DeviceResource{
+		Attributes: map[string]interface{}{contracts.ReportOnChangeKey: true},
+	}, true)
+
+	a := &Agent{
+		driver:           mockDriver,
+		service:          mockService,
+		filter:           filter.New(),
+		filteredCounter:  metrics.NewCounter(),
+		StatusManager:    MockStatusManager(nil),
+		log:              logger.D,
+		FilterAutoEvents: true,
+	}
+	reqs := []models.CommandRequest{
+		{DeviceResourceName: "temperature", Type: common.ValueTypeInt32},
+		{DeviceResourceName: "voltage", Type: common.ValueTypeInt32},
+		{DeviceResourceName: "humidity", Type: common.ValueTypeInt32},
+	}
+
+	responses, err := a.HandleReadCommands("device-001", nil, reqs)
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+
+	// the unchanged readings of the resources polled by AutoEvents are filtered
+	for i := 0; i < 2; i++ {
+		responses, err = a.HandleReadCommands("device-001", nil, reqs)
+		require.NoError(t, err)
+		require.Len(t, responses, 1)
+		require.Equal(t, "humidity", responses[0].DeviceResourceName)
+	}
+	require.EqualValues(t, 4, a.filteredCounter.Count())
+}
+
+func TestAsyncReportWithFilterConcurrently(t *testing.T) {
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("GetDeviceByName", "device-001").Return(edgexmodels.Device{Name: "device-001", ProfileName: "profile"}, nil)
+	mockService.On("DeviceResource", "device-001", "switch").Return(edgexmodels.DeviceResource{
+		Name:       "switch",
+		Attributes: map[string]interface{}{contracts.ReportOnChangeKey: true},
+	}, true)
+
+	a := &Agent{
+		service:         mockService,
+		filter:          filter.New(),
+		filteredCounter: metrics.NewCounter(),
+		buffer:          buffer.New(20, contracts.DropNewest, 0),
+		log:             logger.D,
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, a.ReportEvent(&contracts.AsyncValues{DeviceName: "device-001", SourceName: "source",
+				CommandValues: []*models.CommandValue{{DeviceResourceName: "switch", Value: true}}}))
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, 1, a.buffer.Depth.Value())
+	require.EqualValues(t, 9, a.filteredCounter.Count())
+}
+
+func TestAsyncReportWithFilter(t *testing.T) {
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("GetDeviceByName", "device-001").Return(edgexmodels.Device{Name: "device-001", ProfileName: "profile"}, nil)
+	mockService.On("DeviceResource", "device-001", "switch").Return(edgexmodels.DeviceResource{
+		Name:       "switch",
+		Attributes: map[string]interface{}{contracts.ReportOnChangeKey: true},
+	}, true)
+	mockService.On("DeviceResource", "device-001", "power").Return(edgexmodels.DeviceResource{Name: "power"}, true)
+
+	a := &Agent{
+		service:         mockService,
+		filter:          filter.New(),
+		filteredCounter: metrics.NewCounter(),
+		buffer:          buffer.New(3, contracts.DropNewest, 0),
+		log:             logger.D,
+	}
+	report := func(names ...string) {
+		values := &contracts.AsyncValues{DeviceName: "device-001", SourceName: "source"}
+		for _, name := range names {
+			values.CommandValues = append(values.CommandValues, &models.CommandValue{DeviceResourceName: name, Value: true})
+		}
+		require.NoError(t, a.ReportEvent(values))
+	}
+
+	report("switch", "power")
+	report("switch", "power")
+	report("switch")
+	require.EqualValues(t, 2, a.buffer.Depth.Value())
+
+	values, err := a.buffer.Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, values.CommandValues, 2)
+	values, err = a.buffer.Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, values.CommandValues, 1)
+	require.Equal(t, "power", values.CommandValues[0].DeviceResourceName)
+
+	// the changed value dropped by the full buffer is not recorded, so the equal one is reported again
+	report("power")
+	report("power")
+	report("power")
+	off := &contracts.AsyncValues{DeviceName: "device-001", SourceName: "source", CommandValues: []*models.CommandValue{
+		{DeviceResourceName: "switch", Value: false},
+	}}
+	require.ErrorIs(t, a.ReportEvent(off), buffer.ErrOverflow)
+	for i := 0; i < 3; i++ {
+		_, err = a.buffer.Get(context.Background())
+		require.NoError(t, err)
+	}
+	require.NoError(t, a.ReportEvent(off))
+	require.EqualValues(t, 1, a.buffer.Depth.Value())
+}
+
+func TestAsyncReportWithAggregation(t *testing.T) {
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("GetDeviceByName", "device-001").Return(edgexmodels.Device{Name: "device-001", ProfileName: "profile"}, nil)
+	mockService.On("DeviceResource", "device-001", "x").Return(edgexmodels.DeviceResource{
+		Name: "x",
+		Attributes: map[string]interface{}{
+			contracts.AggregateWindowKey:    "200ms",
+			contracts.AggregateFunctionsKey: "max,avg",
+		},
+	}, true)
+	mockService.On("DeviceResource", "device-001", "state").Return(edgexmodels.DeviceResource{Name: "state"}, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := &Agent{
+		service:         mockService,
+		aggregator:      aggregate.New(),
+		aggregateSignal: make(chan struct{}, 1),
+		buffer:          buffer.New(10, contracts.DropNewest, 0),
+		log:             logger.D,
+	}
+	wg := &sync.WaitGroup{}
+	go a.flushAggregates(ctx, wg)
+
+	for i := 1; i <= 3; i++ {
+		err := a.ReportEventContext(ctx, &contracts.AsyncValues{
+			DeviceName: "device-001",
+			SourceName: "vibration",
+			CommandValues: []*models.CommandValue{
+				{DeviceResourceName: "x", Type: common.ValueTypeInt32, Value: int32(i)},
+				{DeviceResourceName: "state", Type: common.ValueTypeString, Value: "running"},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// the readings without aggregation are passed through
+	for i := 0; i < 3; i++ {
+		values, err := a.buffer.Get(ctx)
+		require.NoError(t, err)
+		require.Len(t, values.CommandValues, 1)
+		require.Equal(t, "state", values.CommandValues[0].DeviceResourceName)
+	}
+
+	summaries := make(map[string]interface{})
+	for i := 0; i < 2; i++ {
+		getCtx, cancelGet := context.WithTimeout(ctx, time.Second)
+		values, err := a.buffer.Get(getCtx)
+		cancelGet()
+		require.NoError(t, err)
+		require.Len(t, values.CommandValues, 1)
+		summaries[values.CommandValues[0].Tags[aggregate.TagKey]] = values.CommandValues[0].Value
+	}
+	require.Equal(t, map[string]interface{}{"max": int32(3), "avg": float64(2)}, summaries)
+
+	cancel()
+	wg.Wait()
+}
+
+func TestHandleWriteCommandsWithValidation(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("WriteProperty", mock.Anything, mock.Anything).Return(nil)
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("GetDeviceByName", "device-001").Return(edgexmodels.Device{Name: "device-001", ProfileName: "profile"}, nil)
+	mockService.On("DeviceResource", "device-001", "switch").Return(edgexmodels.DeviceResource{
+		Name:       "switch",
+		Properties: edgexmodels.ResourceProperties{ReadWrite: common.ReadWrite_RW, Maximum: "1"},
+	}, true)
+	mockService.On("DeviceResource", "device-001", "status").Return(edgexmodels.DeviceResource{
+		Name:       "status",
+		Properties: edgexmodels.ResourceProperties{ReadWrite: common.ReadWrite_R},
+	}, true)
+
+	a := &Agent{driver: mockDriver, service: mockService, StatusManager: MockStatusManager(nil), log: logger.D}
+	reqs := []models.CommandRequest{
+		{DeviceResourceName: "switch", Type: common.ValueTypeInt32},
+		{DeviceResourceName: "status", Type: common.ValueTypeInt32},
+	}
+	on, _ := models.NewCommandValue("switch", common.ValueTypeInt32, int32(1))
+	off, _ := models.NewCommandValue("switch", common.ValueTypeInt32, int32(2))
+	status, _ := models.NewCommandValue("status", common.ValueTypeInt32, int32(1))
+
+	// the invalid ones are rejected without reaching the driver
+	err := a.HandleWriteCommands("device-001", nil, reqs, []*models.CommandValue{off, status})
+	require.ErrorContains(t, err, string(contracts.ParameterParseFailed))
+	require.Equal(t, errors.KindContractInvalid, errors.Kind(err))
+	mockDriver.AssertNotCalled(t, "WriteProperty", mock.Anything, mock.Anything)
+
+	err = a.HandleWriteCommands("device-001", nil, reqs, []*models.CommandValue{on, status})
+	require.ErrorContains(t, err, "read-only")
+	mockDriver.AssertNumberOfCalls(t, "WriteProperty", 1)
+	require.Len(t, mockDriver.Calls[0].Arguments[1], 1)
+
+	a.SkipWriteValidation = true
+	err = a.HandleWriteCommands("device-001", nil, reqs, []*models.CommandValue{off, status})
+	require.NoError(t, err)
+	require.Len(t, mockDriver.Calls[1].Arguments[1], 2)
+}
+
+func TestHandleCommandsWithTransform(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			for _, req := range args[1].([]contracts.ReadRequest) {
+				req.SetResult(contracts.NewSimpleResult(int16(235)).WithCast(true))
+			}
+		},
+	).Return(nil)
+	mockDriver.On("WriteProperty", mock.Anything, mock.Anything).Return(nil)
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("GetDeviceByName", "device-001").Return(edgexmodels.Device{Name: "device-001", ProfileName: "profile"}, nil)
+	mockService.On("DeviceResource", "device-001", mock.Anything).Return(
+		func(_ string, name string) edgexmodels.DeviceResource {
+			properties := edgexmodels.ResourceProperties{ReadWrite: common.ReadWrite_RW, Scale: "10"}
+			if name == "level" {
+				properties.Maximum = "3000"
+			}
+			return edgexmodels.DeviceResource{Name: name, Properties: properties}
+		}, true)
+
+	a := &Agent{driver: mockDriver, service: mockService, StatusManager: MockStatusManager(nil), log: logger.D}
+	reqs := []models.CommandRequest{
+		{DeviceResourceName: "temperature", Type: common.ValueTypeFloat32, Attributes: map[string]interface{}{contracts.ScaleKey: 0.1}},
+		{DeviceResourceName: "raw", Type: common.ValueTypeInt16, Attributes: map[string]interface{}{contracts.TransformKey: false}},
+		{DeviceResourceName: "level", Type: common.ValueTypeInt16},
+	}
+
+	// the properties and attributes are left to the device service and driver by default
+	responses, err := a.HandleReadCommands("device-001", nil, reqs)
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+	require.Equal(t, float32(235), responses[0].Value)
+	require.Equal(t, int16(235), responses[1].Value)
+	require.Equal(t, int16(235), responses[2].Value)
+
+	a.TransformAttributes = true
+	responses, err = a.HandleReadCommands("device-001", nil, reqs)
+	require.NoError(t, err)
+	require.Equal(t, float32(23.5), responses[0].Value)
+	require.Equal(t, int16(235), responses[1].Value)
+	require.Equal(t, int16(235), responses[2].Value)
+
+	a.TransformProperties = true
+	responses, err = a.HandleReadCommands("device-001", nil, reqs)
+	require.NoError(t, err)
+	require.Equal(t, float32(23.5), responses[0].Value)
+	require.Equal(t, int16(235), responses[1].Value)
+	require.Equal(t, int16(2350), responses[2].Value)
+
+	// the parameters are transformed back into the raw values
+	temperature, _ := models.NewCommandValue("temperature", common.ValueTypeFloat32, float32(23.5))
+	level, _ := models.NewCommandValue("level", common.ValueTypeInt16, int16(2350))
+	err = a.HandleWriteCommands("device-001", nil, []models.CommandRequest{reqs[0], reqs[2]}, []*models.CommandValue{temperature, level})
+	require.NoError(t, err)
+	written := mockDriver.Calls[3].Arguments[1].([]contracts.WriteRequest)
+	require.Equal(t, float32(235), written[0].Param().Value)
+	require.Equal(t, int16(235), written[1].Param().Value)
+
+	// the raw value out of range is rejected
+	temperature, _ = models.NewCommandValue("temperature", common.ValueTypeFloat32, float32(1e38))
+	err = a.HandleWriteCommands("device-001", nil, reqs[:1], []*models.CommandValue{temperature})
+	require.ErrorContains(t, err, string(contracts.ParameterParseFailed))
+	mockDriver.AssertNumberOfCalls(t, "WriteProperty", 1)
+
+	// the engineering value is checked against the maximum before transformed
+	level, _ = models.NewCommandValue("level", common.ValueTypeInt16, int16(3010))
+	err = a.HandleWriteCommands("device-001", nil, reqs[2:], []*models.CommandValue{level})
+	require.ErrorContains(t, err, "greater than the maximum")
+	mockDriver.AssertNumberOfCalls(t, "WriteProperty", 1)
+}
+
+func TestPostProcessRequestsWithBatchError(t *testing.T) {
+	a := &Agent{StatusManager: MockStatusManager(nil), log: logger.D}
+
+	reqs := make([]contracts.ReadRequest, 0)
+	for i, name := range []string{"temperature", "humidity", "pressure"} {
+		req := contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: name, Type: common.ValueTypeInt32})
+		if i == 1 {
+			req.Failed(contracts.NewErrorWithReason(contracts.ReadTimeout, "no response"))
+		} else {
+			req.SetResult(contracts.NewSimpleResult(int32(i)))
+		}
+		reqs = append(reqs, req)
+	}
+
+	responses := make([]*models.CommandValue, 0)
+	require.NoError(t, a.PostProcessRequests("device-001", reqs, false, &responses))
+	require.Len(t, responses, 2)
+
+	// all the successful results are collected in strict mode
+	a.StrictMode = true
+	responses = make([]*models.CommandValue, 0)
+	err := a.PostProcessRequests("device-001", reqs, false, &responses)
+	require.Len(t, responses, 2)
+	var batch *contracts.BatchError
+	require.ErrorAs(t, err, &batch)
+	require.Len(t, batch.Errors, 1)
+	require.Equal(t, "humidity", batch.Errors[0].Resource)
+	require.Equal(t, contracts.ReadTimeout, batch.Errors[0].Kind)
+	require.Equal(t, []string{"temperature", "pressure"}, batch.Succeeded)
+	// the batch is responded with the status code of the failed requests
+	var edgexErr errors.EdgeX
+	require.ErrorAs(t, err, &edgexErr)
+	require.Equal(t, http.StatusBadGateway, edgexErr.Code())
+}
+
+func TestPostProcessRequestsWithParseError(t *testing.T) {
+	a := &Agent{StatusManager: MockStatusManager(nil), log: logger.D}
+
+	valid := contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "temperature", Type: common.ValueTypeInt8})
+	valid.SetResult(contracts.NewSimpleResult(int8(1)))
+	overflow := contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "pressure", Type: common.ValueTypeInt8})
+	overflow.SetResult(contracts.NewSimpleResult(int32(1000)).WithCast(true))
+
+	// the results failed to convert are not dropped silently out of strict mode
+	responses := make([]*models.CommandValue, 0)
+	err := a.PostProcessRequests("device-001", []contracts.ReadRequest{valid, overflow}, false, &responses)
+	require.Len(t, responses, 1)
+	var batch *contracts.BatchError
+	require.ErrorAs(t, err, &batch)
+	require.Len(t, batch.Errors, 1)
+	require.Equal(t, "pressure", batch.Errors[0].Resource)
+	require.Equal(t, contracts.DataParseError, batch.Errors[0].Kind)
+}
+
+func TestAsyncReportWithOverflow(t *testing.T) {
+	asyncCh := make(chan *models.AsyncValues)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := &Agent{
+		asyncCh:       asyncCh,
+		log:           logger.D,
+		ctx:           ctx,
+		stop:          cancel,
+		wg:            &sync.WaitGroup{},
+		async:         make(chan *contracts.AsyncValues),
+		buffer:        buffer.New(1, contracts.DropNewest, 0),
+		StatusManager: MockStatusManager(nil),
+	}
+
+	// the values are reported through the channel of driver before the agent starts forwarding
+	go a.bufferAsyncValues(ctx, a.wg)
+	a.async <- &contracts.AsyncValues{DeviceName: "device-001"}
+	require.Eventually(t, func() bool { return a.buffer.Len() == 1 }, time.Second, time.Millisecond*10)
+
+	err := a.ReportEventContext(context.Background(), &contracts.AsyncValues{DeviceName: "device-002"})
+	require.ErrorIs(t, err, buffer.ErrOverflow)
+	require.Equal(t, int64(1), a.buffer.Drops.Count())
+
+	go a.HandleAsyncResults(ctx, a.wg)
+	require.Equal(t, "device-001", (<-asyncCh).DeviceName)
+	require.NoError(t, a.ReportEvent(&contracts.AsyncValues{DeviceName: "device-003"}))
+	require.Equal(t, "device-003", (<-asyncCh).DeviceName)
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/controller/health"
+	"github.com/volcengine/vei-driver-sdk-go/internal/status"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/media"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+const (
+	DefaultProbeTimeout = time.Second * 3
+	// DefaultSaturationPeriod is how long the async queue is full before the driver is not ready.
+	DefaultSaturationPeriod = time.Second * 30
+	// DefaultStallTimeout is how long a command or the forwarding of async values makes no progress before the
+	// driver is regarded as deadlocked.
+	DefaultStallTimeout = time.Minute * 5
+)
+
+// livenessChecks returns the checks of the probe '/health/live', which fail if the driver is deadlocked.
+func (a *Agent) livenessChecks() []health.Check {
+	checks := []health.Check{
+		{Name: "commands", Check: a.checkCommands},
+		{Name: "asyncForwarding", Check: a.checkAsyncForwarding},
+	}
+	if a.health != nil {
+		checks = append(checks, health.Check{Name: "driver", Check: a.health.CheckHealth})
+	}
+	return checks
+}
+
+// readinessChecks returns the checks of the probe '/health/ready', the driver is ready once it is initialized
+// and the built-in checks pass.
+func (a *Agent) readinessChecks() []health.Check {
+	checks := []health.Check{
+		{Name: "initialized", Check: a.checkInitialized},
+		{Name: "asyncQueue", Check: a.checkAsyncQueue},
+		{Name: "statusReporter", Check: checkStatusReporter},
+		{Name: "mediaServer", Check: checkMediaServer},
+	}
+	if a.readiness != nil {
+		checks = append(checks, health.Check{Name: "driver", Check: a.readiness.CheckReadiness})
+	}
+	return checks
+}
+
+func (a *Agent) checkInitialized(context.Context) error {
+	if atomic.LoadInt32(&a.initialized) == 0 {
+		return errors.New("driver is not initialized")
+	}
+	return nil
+}
+
+// checkAsyncQueue fails if the buffer of async values has been full for the SaturationPeriod, or any values are
+// lost within the SaturationPeriod. The buffer is full momentarily on bursts, and the values are evicted or replaced
+// by the DropOldest and Coalesce policies as intended, which don't fail the check.
+func (a *Agent) checkAsyncQueue(context.Context) error {
+	if a.buffer == nil {
+		return nil
+	}
+	if lostAt := a.buffer.LastLoss(); !lostAt.IsZero() && time.Since(lostAt) < a.saturationPeriod() {
+		return fmt.Errorf("async queue lost values %v ago, policy: %s", time.Since(lostAt).Truncate(time.Second), a.buffer.Policy())
+	}
+	if saturated := a.buffer.Saturated(); saturated >= a.saturationPeriod() {
+		return fmt.Errorf("async queue has been saturated for %v, %d values are waiting", saturated.Truncate(time.Second), a.buffer.Len())
+	}
+	return nil
+}
+
+// checkCommands fails if a command has been executed for the StallTimeout, e.g. the driver is deadlocked.
+func (a *Agent) checkCommands(context.Context) error {
+	if a.dispatcher == nil {
+		return nil
+	}
+	if deviceName, duration := a.dispatcher.Longest(); duration >= a.stallTimeout() {
+		return fmt.Errorf("the command of device '%s' has been executed for %v", deviceName, duration.Truncate(time.Second))
+	}
+	return nil
+}
+
+// checkAsyncForwarding fails if no async values have been forwarded for the StallTimeout while some are waiting.
+func (a *Agent) checkAsyncForwarding(context.Context) error {
+	if a.buffer == nil {
+		return nil
+	}
+	if stalled := a.buffer.Stalled(); stalled >= a.stallTimeout() {
+		return fmt.Errorf("no async values have been forwarded for %v, %d values are waiting", stalled.Truncate(time.Second), a.buffer.Len())
+	}
+	return nil
+}
+
+func (a *Agent) saturationPeriod() time.Duration {
+	return utils.Ternary(a.SaturationPeriod <= 0, DefaultSaturationPeriod, a.SaturationPeriod)
+}
+
+func (a *Agent) stallTimeout() time.Duration {
+	return utils.Ternary(a.StallTimeout <= 0, DefaultStallTimeout, a.StallTimeout)
+}
+
+func checkStatusReporter(context.Context) error {
+	if err := status.LastReportError(); err != nil {
+		return fmt.Errorf("report device status failed: %w", err)
+	}
+	return nil
+}
+
+// checkMediaServer dials the media server if it is configured.
+func checkMediaServer(ctx context.Context) error {
+	config := media.Media()
+	if config == nil || config.Server == "" {
+		return nil
+	}
+	u, err := url.Parse(config.Server)
+	if err != nil {
+		return fmt.Errorf("invalid media server '%s': %w", config.Server, err)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), utils.Ternary(u.Scheme == "https", "443", "80"))
+	}
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("media server is unreachable: %w", err)
+	}
+	return conn.Close()
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/buffer"
+	"github.com/volcengine/vei-driver-sdk-go/internal/dispatcher"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+func TestCheckAsyncQueue(t *testing.T) {
+	a := &Agent{buffer: buffer.New(2, contracts.DropNewest, 0), SaturationPeriod: time.Millisecond * 50}
+	ctx := context.Background()
+	values := &contracts.AsyncValues{DeviceName: "device-001"}
+
+	// a momentary burst doesn't fail the check
+	require.NoError(t, a.buffer.Put(ctx, values))
+	require.NoError(t, a.buffer.Put(ctx, values))
+	require.NoError(t, a.checkAsyncQueue(ctx))
+
+	time.Sleep(time.Millisecond * 50)
+	require.ErrorContains(t, a.checkAsyncQueue(ctx), "saturated")
+
+	// the values lost fail the check within the period, however many times it's probed
+	_, err := a.buffer.Get(ctx)
+	require.NoError(t, err)
+	require.NoError(t, a.checkAsyncQueue(ctx))
+	require.NoError(t, a.buffer.Put(ctx, values))
+	require.ErrorIs(t, a.buffer.Put(ctx, values), buffer.ErrOverflow)
+	_, err = a.buffer.Get(ctx)
+	require.NoError(t, err)
+	require.ErrorContains(t, a.checkAsyncQueue(ctx), "lost values")
+	require.ErrorContains(t, a.checkAsyncQueue(ctx), "lost values")
+	time.Sleep(time.Millisecond * 50)
+	require.NoError(t, a.checkAsyncQueue(ctx))
+
+	// the values evicted or replaced by the policies are not lost
+	for _, policy := range []contracts.OverflowPolicy{contracts.DropOldest, contracts.Coalesce} {
+		a.buffer = buffer.New(1, policy, 0)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, a.buffer.Put(ctx, values))
+		}
+		require.Equal(t, int64(2), a.buffer.Drops.Count())
+		require.NoError(t, a.checkAsyncQueue(ctx), policy)
+	}
+}
+
+func TestLivenessChecks(t *testing.T) {
+	a := &Agent{
+		buffer:       buffer.New(2, contracts.Block, 0),
+		dispatcher:   dispatcher.NewDispatcher(2),
+		StallTimeout: time.Millisecond * 50,
+	}
+	ctx := context.Background()
+	for _, check := range a.livenessChecks() {
+		require.NoError(t, check.Check(ctx), check.Name)
+	}
+
+	// the command never returns
+	release, err := a.dispatcher.Acquire(ctx, "device-001")
+	require.NoError(t, err)
+	// the values are never forwarded
+	require.NoError(t, a.buffer.Put(ctx, &contracts.AsyncValues{DeviceName: "device-001"}))
+	time.Sleep(time.Millisecond * 50)
+	require.ErrorContains(t, a.checkCommands(ctx), "device-001")
+	require.ErrorContains(t, a.checkAsyncForwarding(ctx), "no async values have been forwarded")
+
+	release()
+	_, err = a.buffer.Get(ctx)
+	require.NoError(t, err)
+	for _, check := range a.livenessChecks() {
+		require.NoError(t, check.Check(ctx), check.Name)
+	}
+}
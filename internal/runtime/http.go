@@ -17,25 +17,42 @@
 package runtime
 
 import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/gorilla/mux"
 
 	"github.com/volcengine/vei-driver-sdk-go/internal/controller/debug"
 	"github.com/volcengine/vei-driver-sdk-go/internal/controller/discovery"
+	"github.com/volcengine/vei-driver-sdk-go/internal/controller/health"
 	"github.com/volcengine/vei-driver-sdk-go/internal/controller/hook"
+	"github.com/volcengine/vei-driver-sdk-go/internal/middleware"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/media"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
 )
 
 const (
-	CustomizedServerPort = ":9999"
+	CustomizedServerPort   = ":9999"
+	DefaultShutdownTimeout = time.Second * 5
 )
 
 const (
 	ApiDebugRoute     = common.ApiBase + "/debug"
 	ApiDebugLogging   = common.ApiBase + "/logging"
+	ApiDebugPanics    = common.ApiBase + "/debug/panics"
 	ApiDiscoveryRoute = common.ApiBase + "/device/discovery"
 
+	ApiHealthLive  = "/health/live"
+	ApiHealthReady = "/health/ready"
+
+	ApiHookRoutePrefix             = common.ApiBase + "/hook/"
 	ApiHookOnStreamNotFoundRoute   = common.ApiBase + "/hook/on_stream_not_found"
 	ApiHookOnStreamNoneReaderRoute = common.ApiBase + "/hook/on_stream_none_reader"
 )
@@ -46,29 +63,121 @@ type Route struct {
 	method  []string
 }
 
+// NewRoute creates a route served by the customized server.
+func NewRoute(route string, handler func(http.ResponseWriter, *http.Request), methods ...string) Route {
+	return Route{route: route, handler: handler, method: methods}
+}
+
 func (a *Agent) RegisterRoutes() error {
+	// The panics raised by the discovery and webhook of driver are recovered.
+	var safeDiscover interfaces.Discovery
+	if a.discovery != nil {
+		safeDiscover = &safeDiscovery{Discovery: a.discovery, agent: a}
+	}
+	var safeHook interfaces.Webhook
+	if a.webhook != nil {
+		safeHook = &safeWebhook{Webhook: a.webhook, agent: a}
+	}
+
 	routes := []Route{
 		{route: ApiDebugRoute, handler: debug.Debug(a.debugger), method: []string{http.MethodPost}},
 		{route: ApiDebugLogging, handler: debug.SetDefaultLogLevel, method: []string{http.MethodGet, http.MethodPost}},
-		{route: ApiDiscoveryRoute, handler: discovery.Discover(a.discovery), method: []string{http.MethodGet, http.MethodPost}},
-		{route: ApiHookOnStreamNotFoundRoute, handler: hook.OnStreamNotFound(a.webhook), method: []string{http.MethodPost}},
-		{route: ApiHookOnStreamNoneReaderRoute, handler: hook.OnStreamNoneReader(a.webhook), method: []string{http.MethodPost}},
+		{route: ApiDebugPanics, handler: debug.Panics(a.PanicCounts), method: []string{http.MethodGet}},
+		{route: ApiDiscoveryRoute, handler: discovery.Discover(safeDiscover), method: []string{http.MethodGet, http.MethodPost}},
+		{route: ApiHookOnStreamNotFoundRoute, handler: hook.OnStreamNotFound(safeHook), method: []string{http.MethodPost}},
+		{route: ApiHookOnStreamNoneReaderRoute, handler: hook.OnStreamNoneReader(safeHook), method: []string{http.MethodPost}},
+	}
+
+	// The routes of driver are registered after the built-in ones which take precedence.
+	routes = append(routes, a.Routes...)
+
+	security, err := a.securityConfig()
+	if err != nil {
+		return fmt.Errorf("invalid security config of customized server: %w", err)
+	}
+	tlsConfig, err := security.TLSConfig()
+	if err != nil {
+		return err
 	}
 
+	// The probes are not authenticated so that they can be called by the kubelet.
 	router := mux.NewRouter()
+	router.HandleFunc(ApiHealthLive, health.Probe(a.livenessChecks, DefaultProbeTimeout)).Methods(http.MethodGet)
+	router.HandleFunc(ApiHealthReady, health.Probe(a.readinessChecks, DefaultProbeTimeout)).Methods(http.MethodGet)
+
+	// The hooks called by media server are verified by the media secret, and the others by the auth mode.
+	hooks := router.MatcherFunc(func(request *http.Request, _ *mux.RouteMatch) bool {
+		return strings.HasPrefix(request.URL.Path, ApiHookRoutePrefix)
+	}).Subrouter()
+	apis := router.NewRoute().Subrouter()
+	if security.Authenticated() {
+		hooks.Use(middleware.VerifyMediaSecret(mediaSecret))
+		apis.Use(middleware.Authenticate(security))
+	}
 	for _, route := range routes {
-		router.HandleFunc(route.route, route.handler).Methods(route.method...)
+		r := utils.Ternary(strings.HasPrefix(route.route, ApiHookRoutePrefix), hooks, apis).HandleFunc(route.route, route.handler)
+		if len(route.method) > 0 {
+			r.Methods(route.method...)
+		}
 	}
 
-	server := &http.Server{
-		Addr:    CustomizedServerPort,
-		Handler: router,
+	address := utils.Ternary(a.HTTPAddress == "", CustomizedServerPort, a.HTTPAddress)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("customized server listens on '%s' failed: %w", address, err)
+	}
+	a.server = &http.Server{Handler: router, TLSConfig: tlsConfig}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
 	}
+	a.log.Infof("Customized server is listening on %s, TLS: %v, auth mode: %s",
+		listener.Addr(), tlsConfig != nil, utils.Ternary(security.AuthMode == "", middleware.AuthNone, security.AuthMode))
 
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			panic(err)
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			a.log.Errorf("customized server stopped unexpectedly: %v", err)
 		}
-	}()
+	}(a.server)
+	return nil
+}
+
+// securityConfig loads the security config of the customized server from the driver config, which is
+// overridden by the HTTPSecurity of agent.
+func (a *Agent) securityConfig() (*middleware.Config, error) {
+	config := &middleware.Config{}
+	if a.service != nil {
+		loaded, err := middleware.LoadConfig(a.service.DriverConfigs())
+		if err != nil {
+			return nil, err
+		}
+		config = loaded
+	}
+	config.Merge(a.HTTPSecurity)
+	return config, config.Validate()
+}
+
+// mediaSecret returns the secret of media server, empty if the media config is not initialized.
+func mediaSecret() string {
+	config := media.Media()
+	if config == nil {
+		return ""
+	}
+	return config.Secret
+}
+
+// ShutdownServer shuts down the customized server gracefully, the active connections are closed forcibly if
+// they are not drained within the HTTPShutdownTimeout.
+func (a *Agent) ShutdownServer() error {
+	if a.server == nil {
+		return nil
+	}
+	timeout := utils.Ternary(a.HTTPShutdownTimeout <= 0, DefaultShutdownTimeout, a.HTTPShutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := a.server.Shutdown(ctx); err != nil {
+		a.log.Warnf("customized server is not drained within %v, closing it: %v", timeout, err)
+		return a.server.Close()
+	}
 	return nil
 }
@@ -17,13 +17,144 @@
 package runtime
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/buffer"
+	"github.com/volcengine/vei-driver-sdk-go/internal/controller/health"
+	"github.com/volcengine/vei-driver-sdk-go/internal/middleware"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces/mocks"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
 )
 
 func TestAgent_RegisterRoutes(t *testing.T) {
-	a := &Agent{}
-	err := a.RegisterRoutes()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	a := &Agent{
+		log:         logger.D,
+		HTTPAddress: address,
+		Routes: []Route{
+			NewRoute("/api/v2/driver/ping", func(writer http.ResponseWriter, request *http.Request) {
+				_, _ = writer.Write([]byte("pong"))
+			}, http.MethodGet),
+		},
+	}
+	err = a.RegisterRoutes()
+	require.NoError(t, err)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/api/v2/driver/ping", address))
 	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, "pong", string(body))
+
+	// the address is in use
+	b := &Agent{log: logger.D, HTTPAddress: address}
+	require.Error(t, b.RegisterRoutes())
+
+	a.HTTPShutdownTimeout = time.Second
+	require.NoError(t, a.ShutdownServer())
+	_, err = http.Get(fmt.Sprintf("http://%s/api/v2/driver/ping", address))
+	require.Error(t, err)
+	require.NoError(t, b.ShutdownServer())
+}
+
+func TestAgent_RegisterRoutesWithAuth(t *testing.T) {
+	a := &Agent{
+		log:          logger.D,
+		HTTPAddress:  "127.0.0.1:0",
+		HTTPSecurity: middleware.Config{AuthMode: middleware.AuthBearer, AuthToken: "token"},
+	}
+	require.NoError(t, a.RegisterRoutes())
+	defer func() {
+		_ = a.ShutdownServer()
+	}()
+
+	request := httptest.NewRequest(http.MethodGet, ApiDebugPanics, http.NoBody)
+	recorder := httptest.NewRecorder()
+	a.server.Handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	request.Header.Set(middleware.HeaderAuthorization, "Bearer token")
+	recorder = httptest.NewRecorder()
+	a.server.Handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// the hooks are verified by the media secret rather than the token
+	request = httptest.NewRequest(http.MethodPost, ApiHookOnStreamNotFoundRoute, http.NoBody)
+	request.Header.Set(middleware.HeaderAuthorization, "Bearer token")
+	recorder = httptest.NewRecorder()
+	a.server.Handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	b := &Agent{log: logger.D, HTTPSecurity: middleware.Config{AuthMode: middleware.AuthHMAC}}
+	require.Error(t, b.RegisterRoutes())
+}
+
+func TestAgent_HealthProbes(t *testing.T) {
+	mockReadiness := &mocks.ReadinessChecker{}
+	mockReadiness.On("CheckReadiness", mock.Anything).Return(nil).Once()
+	mockReadiness.On("CheckReadiness", mock.Anything).Return(errors.New("backend disconnected"))
+	a := &Agent{
+		log:          logger.D,
+		HTTPAddress:  "127.0.0.1:0",
+		HTTPSecurity: middleware.Config{AuthMode: middleware.AuthBearer, AuthToken: "token"},
+		readiness:    mockReadiness,
+		buffer:       buffer.New(1, contracts.DropNewest, 0),
+	}
+	require.NoError(t, a.RegisterRoutes())
+	defer func() {
+		_ = a.ShutdownServer()
+	}()
+	probe := func(route string) (int, health.Report) {
+		recorder := httptest.NewRecorder()
+		a.server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, route, http.NoBody))
+		var report health.Report
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+		return recorder.Code, report
+	}
+
+	// the probes are not authenticated, and the driver is alive without HealthChecker
+	code, report := probe(ApiHealthLive)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, health.StatusUp, report.Status)
+
+	// not ready until initialized
+	code, report = probe(ApiHealthReady)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, "driver is not initialized", report.Checks["initialized"])
+	require.Equal(t, health.StatusUp, report.Checks["driver"])
+
+	atomic.StoreInt32(&a.initialized, 1)
+	code, report = probe(ApiHealthReady)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, health.StatusUp, report.Checks["initialized"])
+	require.Equal(t, "backend disconnected", report.Checks["driver"])
+
+	// the async queue is full momentarily, and then loses the values
+	require.NoError(t, a.buffer.Put(context.Background(), &contracts.AsyncValues{}))
+	_, report = probe(ApiHealthReady)
+	require.Equal(t, health.StatusUp, report.Checks["asyncQueue"])
+	require.Error(t, a.buffer.Put(context.Background(), &contracts.AsyncValues{}))
+	for i := 0; i < 2; i++ {
+		_, report = probe(ApiHealthReady)
+		require.Equal(t, "async queue lost values 0s ago, policy: dropNewest", report.Checks["asyncQueue"])
+	}
 }
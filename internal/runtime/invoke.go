@@ -0,0 +1,196 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interceptor"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+func (a *Agent) readProperty(device *contracts.Device, reqs []contracts.ReadRequest) error {
+	inv := &interceptor.Invocation{Operation: interceptor.Read, Device: device, ReadRequests: reqs}
+	return a.intercept(inv, func(inv *interceptor.Invocation) error {
+		return retryRequests(a, inv.Device, inv.ReadRequests, a.invokeReadProperty)
+	})
+}
+
+func (a *Agent) writeProperty(device *contracts.Device, reqs []contracts.WriteRequest) error {
+	inv := &interceptor.Invocation{Operation: interceptor.Write, Device: device, WriteRequests: reqs}
+	return a.intercept(inv, func(inv *interceptor.Invocation) error {
+		return retryRequests(a, inv.Device, inv.WriteRequests, a.invokeWriteProperty)
+	})
+}
+
+func (a *Agent) callService(device *contracts.Device, reqs []contracts.CallRequest) error {
+	inv := &interceptor.Invocation{Operation: interceptor.Call, Device: device, CallRequests: reqs}
+	return a.intercept(inv, func(inv *interceptor.Invocation) error {
+		return retryRequests(a, inv.Device, inv.CallRequests, a.invokeCallService)
+	})
+}
+
+// pollProperty polls the resources of device through the interceptors in the same way as readProperty, the
+// invocation is cancelled once ctx is done.
+func (a *Agent) pollProperty(ctx context.Context, device *contracts.Device, reqs []contracts.ReadRequest) error {
+	inv := &interceptor.Invocation{Operation: interceptor.Read, Device: device, ReadRequests: reqs}
+	return a.intercept(inv, func(inv *interceptor.Invocation) error {
+		return retryRequests(a, inv.Device, inv.ReadRequests, func(device *contracts.Device, reqs []contracts.ReadRequest) error {
+			return a.invokePollProperty(ctx, device, reqs)
+		})
+	})
+}
+
+// reportValues forwards the async values reported by driver to the device service.
+func (a *Agent) reportValues(ctx context.Context, values *contracts.AsyncValues) error {
+	inv := &interceptor.Invocation{Operation: interceptor.Report, Device: a.lookupDevice(values.DeviceName), Values: values}
+	return a.intercept(inv, func(inv *interceptor.Invocation) error {
+		select {
+		case a.asyncCh <- inv.Values.Transform():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// intercept performs the invocation through the chain of interceptors, the panics recovered by the interceptors
+// are counted like the ones raised by driver.
+func (a *Agent) intercept(inv *interceptor.Invocation, invoker interceptor.Invoker) error {
+	if len(a.Interceptors) == 0 {
+		return invoker(inv)
+	}
+	inv.Recover = func(r interface{}) error {
+		return a.onPanic(inv.Device.Name, inv.Operation.String(), strings.Join(resourceNames(inv.Requests()), ","), r)
+	}
+	return interceptor.Chain(invoker, a.Interceptors...)(inv)
+}
+
+// lookupDevice wraps the device with the protocols known by the device service.
+func (a *Agent) lookupDevice(deviceName string) *contracts.Device {
+	if a.service != nil {
+		if device, err := a.service.GetDeviceByName(deviceName); err == nil {
+			return a.wrapDevice(deviceName, device.Protocols)
+		}
+	}
+	return a.wrapDevice(deviceName, nil)
+}
+
+// profileName returns the name of the profile of the device, or empty if the device is not found.
+func (a *Agent) profileName(deviceName string) string {
+	if a.service != nil {
+		if device, err := a.service.GetDeviceByName(deviceName); err == nil {
+			return device.ProfileName
+		}
+	}
+	return ""
+}
+
+// bindProfile binds the requests to the profile, so that the attributes decoded by driver are cached per profile.
+func bindProfile[T contracts.BaseRequest](profile string, reqs []T) {
+	for _, req := range reqs {
+		contracts.SetProfile(req, profile)
+	}
+}
+
+func (a *Agent) invokeReadProperty(device *contracts.Device, reqs []contracts.ReadRequest) (err error) {
+	defer recoverRequests(a, device.Name, "ReadProperty", reqs, &err)
+	if a.ctxDriver == nil {
+		return a.driver.ReadProperty(device, reqs)
+	}
+	ctx, cancel := commandContext(a.context(), a, reqs)
+	defer cancel()
+	err = a.ctxDriver.ReadPropertyContext(ctx, device, reqs)
+	return timeoutRequests(ctx, reqs, contracts.ReadTimeout, false, err)
+}
+
+func (a *Agent) invokePollProperty(parent context.Context, device *contracts.Device, reqs []contracts.ReadRequest) (err error) {
+	defer recoverRequests(a, device.Name, "PollProperty", reqs, &err)
+	ctx, cancel := commandContext(parent, a, reqs)
+	defer cancel()
+	err = a.poller.PollProperty(ctx, device, reqs)
+	return timeoutRequests(ctx, reqs, contracts.ReadTimeout, false, err)
+}
+
+func (a *Agent) invokeWriteProperty(device *contracts.Device, reqs []contracts.WriteRequest) (err error) {
+	defer recoverRequests(a, device.Name, "WriteProperty", reqs, &err)
+	if a.ctxDriver == nil {
+		return a.driver.WriteProperty(device, reqs)
+	}
+	ctx, cancel := commandContext(a.context(), a, reqs)
+	defer cancel()
+	err = a.ctxDriver.WritePropertyContext(ctx, device, reqs)
+	return timeoutRequests(ctx, reqs, contracts.WriteTimeout, true, err)
+}
+
+func (a *Agent) invokeCallService(device *contracts.Device, reqs []contracts.CallRequest) (err error) {
+	defer recoverRequests(a, device.Name, "CallService", reqs, &err)
+	if a.ctxDriver == nil {
+		return a.driver.CallService(device, reqs)
+	}
+	ctx, cancel := commandContext(a.context(), a, reqs)
+	defer cancel()
+	err = a.ctxDriver.CallServiceContext(ctx, device, reqs)
+	// The services are usually not idempotent, so the calls timed out fail as CallTimeout which is not retried
+	// by default.
+	return timeoutRequests(ctx, reqs, contracts.CallTimeout, false, err)
+}
+
+// commandContext derives a context from the parent, which is usually the context of agent cancelled when the agent
+// stops. The deadline applies to the whole batch of requests, which is the longest timeout defined in the attributes
+// of the requests, or the CommandTimeout if none is defined. So the shorter timeouts in the same batch are not
+// enforced separately.
+func commandContext[T contracts.BaseRequest](parent context.Context, a *Agent, reqs []T) (context.Context, context.CancelFunc) {
+
+	var timeout time.Duration
+	for _, req := range reqs {
+		if t, ok := contracts.GetResourceDuration(req.Attributes(), contracts.TimeoutKey); ok && t > timeout {
+			timeout = t
+		}
+	}
+	timeout = utils.Ternary(timeout == 0, a.CommandTimeout, timeout)
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// timeoutRequests fails the requests left unfinished with the specified kind of error if the deadline exceeds,
+// the deadline error returned by driver is converted to the errors of the requests.
+func timeoutRequests[T contracts.BaseRequest](ctx context.Context, reqs []T, kind contracts.ErrorKind, write bool, err error) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	exceeded := errors.Is(err, context.DeadlineExceeded)
+	if write && !exceeded {
+		return err
+	}
+	for _, req := range reqs {
+		if req.Skipped() || req.Error() != nil || !write && req.Result() != nil {
+			continue
+		}
+		req.Failed(contracts.NewError(kind, ctx.Err()))
+	}
+	if exceeded {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interceptor"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces/mocks"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+func MockBlockedContextDriver() *mocks.ContextDriver {
+	wait := func(args mock.Arguments) {
+		<-args[0].(context.Context).Done()
+	}
+	mockDriver := &mocks.ContextDriver{}
+	mockDriver.On("ReadPropertyContext", mock.Anything, mock.Anything, mock.Anything).Run(wait).Return(context.DeadlineExceeded)
+	mockDriver.On("WritePropertyContext", mock.Anything, mock.Anything, mock.Anything).Run(wait).Return(context.DeadlineExceeded)
+	mockDriver.On("CallServiceContext", mock.Anything, mock.Anything, mock.Anything).Run(wait).Return(context.Canceled)
+	return mockDriver
+}
+
+func TestCommandContext(t *testing.T) {
+	a := &Agent{CommandTimeout: time.Second}
+	short := contracts.NewReadRequest(models.CommandRequest{Attributes: map[string]interface{}{contracts.TimeoutKey: "100ms"}})
+	long := contracts.NewReadRequest(models.CommandRequest{Attributes: map[string]interface{}{contracts.TimeoutKey: "2s"}})
+	none := contracts.NewReadRequest(models.CommandRequest{})
+
+	ctx, cancel := commandContext(a.context(), a, []contracts.ReadRequest{none})
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Millisecond*100)
+	cancel()
+
+	ctx, cancel = commandContext(a.context(), a, []contracts.ReadRequest{none, short, long})
+	deadline, ok = ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Second*2), deadline, time.Millisecond*100)
+	cancel()
+
+	a.CommandTimeout = 0
+	ctx, cancel = commandContext(a.context(), a, []contracts.ReadRequest{none})
+	_, ok = ctx.Deadline()
+	require.False(t, ok)
+	cancel()
+}
+
+func TestInvokeContextDriver(t *testing.T) {
+	a := &Agent{ctxDriver: MockBlockedContextDriver(), log: logger.D, CommandTimeout: time.Millisecond * 50}
+	device := contracts.WrapDevice("device", nil)
+
+	read := contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "temperature", Type: common.ValueTypeFloat32})
+	finished := contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "humidity", Type: common.ValueTypeFloat32})
+	finished.SetResult(contracts.NewSimpleResult(float32(1.0)))
+	err := a.readProperty(device, []contracts.ReadRequest{read, finished})
+	require.NoError(t, err)
+	require.ErrorContains(t, read.Error(), string(contracts.ReadTimeout))
+	require.NoError(t, finished.Error())
+
+	write := contracts.NewWriteRequest(models.CommandRequest{DeviceResourceName: "switch", Type: common.ValueTypeBool}, nil)
+	err = a.writeProperty(device, []contracts.WriteRequest{write})
+	require.NoError(t, err)
+	require.ErrorContains(t, write.Error(), string(contracts.WriteTimeout))
+
+	// the calls timed out are not retried by default
+	a.RetryPolicy = contracts.RetryPolicy{MaxAttempts: 3}
+	call, _ := contracts.NewCallRequest(models.CommandRequest{DeviceResourceName: "reboot", Type: common.ValueTypeObject})
+	_ = a.callService(device, []contracts.CallRequest{call})
+	require.ErrorIs(t, call.Error(), contracts.NewErrorWithReason(contracts.CallTimeout, ""))
+	a.ctxDriver.(*mocks.ContextDriver).AssertNumberOfCalls(t, "CallServiceContext", 1)
+
+	// cancelled when the agent is stopping
+	a.RetryPolicy = contracts.RetryPolicy{}
+	a.CommandTimeout = 0
+	a.ctx, a.stop = context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*50, a.stop)
+	call, _ = contracts.NewCallRequest(models.CommandRequest{DeviceResourceName: "reboot", Type: common.ValueTypeObject})
+	err = a.callService(device, []contracts.CallRequest{call})
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, call.Error(), context.Canceled)
+}
+
+func TestInvokeWithInterceptors(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Return(nil)
+
+	operations := make([]interceptor.Operation, 0)
+	asyncCh := make(chan *models.AsyncValues, 1)
+	a := &Agent{
+		driver:  mockDriver,
+		asyncCh: asyncCh,
+		log:     logger.D,
+		Interceptors: []interceptor.Interceptor{
+			func(inv *interceptor.Invocation, next interceptor.Invoker) error {
+				operations = append(operations, inv.Operation)
+				return next(inv)
+			},
+			func(inv *interceptor.Invocation, next interceptor.Invoker) error {
+				if inv.Operation == interceptor.Call {
+					return errors.New("service is disabled")
+				}
+				return next(inv)
+			},
+		},
+	}
+	device := contracts.WrapDevice("device", nil)
+
+	err := a.readProperty(device, []contracts.ReadRequest{contracts.NewReadRequest(models.CommandRequest{})})
+	require.NoError(t, err)
+	mockDriver.AssertNumberOfCalls(t, "ReadProperty", 1)
+
+	req, err := contracts.NewCallRequest(models.CommandRequest{DeviceResourceName: "service"})
+	require.NoError(t, err)
+	err = a.callService(device, []contracts.CallRequest{req})
+	require.EqualError(t, err, "service is disabled")
+	mockDriver.AssertNotCalled(t, "CallService", mock.Anything, mock.Anything)
+
+	err = a.reportValues(context.Background(), &contracts.AsyncValues{DeviceName: "device"})
+	require.NoError(t, err)
+	require.Equal(t, "device", (<-asyncCh).DeviceName)
+	require.Equal(t, []interceptor.Operation{interceptor.Read, interceptor.Call, interceptor.Report}, operations)
+}
+
+func TestInvokeWithPanickedInterceptor(t *testing.T) {
+	a := &Agent{
+		log: logger.D,
+		Interceptors: []interceptor.Interceptor{
+			interceptor.Recovery(nil),
+			func(inv *interceptor.Invocation, next interceptor.Invoker) error {
+				panic("unexpected")
+			},
+		},
+	}
+	req := contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "temperature"})
+	err := a.readProperty(contracts.WrapDevice("device", nil), []contracts.ReadRequest{req})
+	require.ErrorIs(t, err, contracts.NewErrorWithReason(contracts.DriverPanic, ""))
+	require.ErrorIs(t, req.Error(), contracts.NewErrorWithReason(contracts.DriverPanic, ""))
+
+	// the panics of the interceptors are counted like the ones of driver
+	require.Equal(t, map[string]int64{"device": 1}, a.PanicCounts())
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/volcengine/vei-driver-sdk-go/extension/requests"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces"
+)
+
+// recoverPanic recovers the panic raised by the callback of driver and converts it into the DriverPanic error,
+// which is counted as a failure of the device if specified. It must be deferred directly.
+func (a *Agent) recoverPanic(deviceName string, operation string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	*err = a.onPanic(deviceName, operation, "", r)
+	if deviceName != "" && a.StatusManager != nil {
+		a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
+	}
+}
+
+// recoverRequests recovers the panic raised by driver when handling the requests, the unfinished requests are
+// failed with the DriverPanic error, which are counted as failures when post-processed. It must be deferred directly.
+func recoverRequests[T contracts.BaseRequest](a *Agent, deviceName string, operation string, reqs []T, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	e := a.onPanic(deviceName, operation, strings.Join(resourceNames(reqs), ","), r)
+	for _, req := range reqs {
+		if req.Skipped() || req.Error() != nil || req.Result() != nil {
+			continue
+		}
+		req.Failed(e)
+	}
+	*err = nil
+}
+
+// onPanic logs the stack of the panic and increases the panic count of the device.
+func (a *Agent) onPanic(deviceName string, operation string, resources string, r interface{}) *contracts.Error {
+	a.log.Errorf("driver panicked in %s, device: '%s', resources: '%s', panic: %v\n%s",
+		operation, deviceName, resources, r, debug.Stack())
+
+	a.panicMutex.Lock()
+	if a.panics == nil {
+		a.panics = make(map[string]int64)
+	}
+	a.panics[deviceName]++
+	a.panicMutex.Unlock()
+	if a.panicCounter != nil {
+		a.panicCounter.Inc(1)
+	}
+	return contracts.NewErrorWithReason(contracts.DriverPanic, fmt.Sprintf("panic in %s: %v", operation, r))
+}
+
+// PanicCounts returns the number of panics raised by driver of each device, the panics not related to any device
+// are counted with the empty name.
+func (a *Agent) PanicCounts() map[string]int64 {
+	a.panicMutex.Lock()
+	defer a.panicMutex.Unlock()
+
+	counts := make(map[string]int64, len(a.panics))
+	for name, count := range a.panics {
+		counts[name] = count
+	}
+	return counts
+}
+
+// safeDiscovery recovers the panic raised by Discover of driver.
+type safeDiscovery struct {
+	interfaces.Discovery
+	agent *Agent
+}
+
+func (d *safeDiscovery) Discover(ctx context.Context, param *requests.DiscoveryParameter, deviceCh chan<- *contracts.Device) {
+	var err error
+	defer d.agent.recoverPanic("", "Discover", &err)
+	d.Discovery.Discover(ctx, param, deviceCh)
+}
+
+// safeWebhook recovers the panic raised by the hooks of driver.
+type safeWebhook struct {
+	interfaces.Webhook
+	agent *Agent
+}
+
+func (w *safeWebhook) OnStreamNotFound(ctx context.Context, schema string, deviceName string) (err error) {
+	defer w.agent.recoverPanic(deviceName, "OnStreamNotFound", &err)
+	return w.Webhook.OnStreamNotFound(ctx, schema, deviceName)
+}
+
+func (w *safeWebhook) OnStreamNoneReader(ctx context.Context, schema string, deviceName string) (err error) {
+	defer w.agent.recoverPanic(deviceName, "OnStreamNoneReader", &err)
+	return w.Webhook.OnStreamNoneReader(ctx, schema, deviceName)
+}
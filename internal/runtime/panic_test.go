@@ -0,0 +1,86 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces/mocks"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+type panicWebhook struct{}
+
+func (panicWebhook) OnStreamNotFound(_ context.Context, _ string, _ string) error {
+	panic("stream not found")
+}
+
+func (panicWebhook) OnStreamNoneReader(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
+func TestRecoverDriverPanic(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			reqs := args[1].([]contracts.ReadRequest)
+			reqs[0].SetResult(contracts.NewSimpleResult(int32(1)))
+			var device *contracts.Device
+			_ = device.Name
+		},
+	).Return(nil)
+	mockHandler := &mocks.DeviceHandler{}
+	mockHandler.On("AddDevice", mock.Anything).Run(func(args mock.Arguments) { panic("add device") }).Return(nil)
+
+	a := &Agent{
+		driver:        mockDriver,
+		handler:       mockHandler,
+		StatusManager: MockStatusManager(map[string]*contracts.Device{}),
+		StrictMode:    true,
+		log:           logger.D,
+	}
+
+	reqs := []models.CommandRequest{
+		{DeviceResourceName: "temperature", Type: common.ValueTypeInt32},
+		{DeviceResourceName: "humidity", Type: common.ValueTypeInt32},
+	}
+	responses, err := a.HandleReadCommands("device-001", nil, reqs)
+	require.Len(t, responses, 1)
+	var batch *contracts.BatchError
+	require.ErrorAs(t, err, &batch)
+	require.Len(t, batch.Errors, 1)
+	require.Equal(t, "humidity", batch.Errors[0].Resource)
+	require.Equal(t, contracts.DriverPanic, batch.Errors[0].Kind)
+
+	err = a.AddDevice("device-002", nil, "")
+	kind, ok := contracts.KindOf(err)
+	require.True(t, ok)
+	require.Equal(t, contracts.DriverPanic, kind)
+
+	hook := &safeWebhook{Webhook: panicWebhook{}, agent: a}
+	require.Error(t, hook.OnStreamNotFound(context.Background(), "rtsp", "device-001"))
+	require.NoError(t, hook.OnStreamNoneReader(context.Background(), "rtsp", "device-001"))
+
+	require.Equal(t, map[string]int64{"device-001": 2, "device-002": 1}, a.PanicCounts())
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/scheduler"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+// startPolling starts the polling scheduler if the driver implements Poller, and schedules the existing devices.
+func (a *Agent) startPolling() {
+	if a.poller == nil {
+		return
+	}
+	a.scheduler = scheduler.New(a.context(), a.pollDevice, a.PollJitter)
+	for _, device := range a.service.Devices() {
+		a.schedulePolling(device.Name)
+	}
+	a.log.Infof("Start polling %d devices, jitter: %v", len(a.scheduler.Devices()), a.PollJitter)
+}
+
+// schedulePolling polls the resources of the device grouped by the attribute 'pollInterval', the groups scheduled
+// before are replaced so that the changes of the device profile take effect.
+func (a *Agent) schedulePolling(deviceName string) {
+	if a.scheduler == nil {
+		return
+	}
+	if a.isLocked(deviceName) {
+		a.log.Debugf("stop polling device '%s' which is locked", deviceName)
+		a.scheduler.Unschedule(deviceName)
+		return
+	}
+	device, err := a.service.GetDeviceByName(deviceName)
+	if err != nil {
+		a.log.Warnf("stop polling device '%s' which is not found: %v", deviceName, err)
+		a.scheduler.Unschedule(deviceName)
+		return
+	}
+	profile, err := a.service.GetProfileByName(device.ProfileName)
+	if err != nil {
+		a.log.Warnf("stop polling device '%s' whose profile '%s' is not found: %v", deviceName, device.ProfileName, err)
+		a.scheduler.Unschedule(deviceName)
+		return
+	}
+	groups := scheduler.Group(profile.DeviceResources)
+	for interval, resources := range groups {
+		a.log.Debugf("poll %d resources of device '%s' every %v", len(resources), deviceName, interval)
+	}
+	a.scheduler.Schedule(deviceName, groups)
+}
+
+// pollDevice polls the group of resources of the device, and reports the results through the Reporter.
+func (a *Agent) pollDevice(ctx context.Context, deviceName string, interval time.Duration, resources []models.DeviceResource) {
+	release, err := a.acquire(deviceName)
+	if err != nil {
+		return
+	}
+	defer release()
+
+	device := a.lookupDevice(deviceName)
+	reqs := make([]contracts.ReadRequest, 0, len(resources))
+	for _, resource := range resources {
+		reqs = append(reqs, contracts.NewReadRequest(sdkmodels.CommandRequest{
+			DeviceResourceName: resource.Name,
+			Attributes:         resource.Attributes,
+			Type:               resource.Properties.ValueType,
+		}))
+	}
+	bindProfile(a.profileName(deviceName), reqs)
+	if err = a.pollProperty(ctx, device, reqs); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		a.log.Warnf("poll device '%s' failed: %v", deviceName, err)
+		a.PostProcessDevice(device, err)
+		a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
+		return
+	}
+	a.transformResults(deviceName, reqs)
+
+	// The successful readings are counted once they are delivered.
+	values := &contracts.AsyncValues{DeviceName: deviceName, SourceName: pollSource(interval, resources)}
+	for _, req := range reqs {
+		resourceName := req.Native().DeviceResourceName
+		if err = req.Error(); err != nil {
+			a.log.Debugf("poll '%s' of device '%s' failed: %v", resourceName, deviceName, err)
+			a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
+			continue
+		}
+		if req.Skipped() || req.Result() == nil {
+			continue
+		}
+		cv, err := req.Result().CommandValue(resourceName, req.Native().Type)
+		if err != nil {
+			a.log.Debugf("poll '%s' of device '%s' failed: %v", resourceName, deviceName, err)
+			a.StatusManager.OnHandleCommandsFailed(deviceName, 1)
+			continue
+		}
+		values.CommandValues = append(values.CommandValues, cv)
+	}
+	if len(values.CommandValues) > 0 {
+		_ = a.reporter.ReportEventContext(ctx, values)
+	}
+}
+
+// pollSource names the source of the polled readings by the resource, or by the interval if there are more than one.
+func pollSource(interval time.Duration, resources []models.DeviceResource) string {
+	if len(resources) == 1 {
+		return resources[0].Name
+	}
+	return fmt.Sprintf("poll-%v", interval)
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdkmocks "github.com/edgexfoundry/device-sdk-go/v2/pkg/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/buffer"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces/mocks"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+func TestPollDevice(t *testing.T) {
+	mockPoller := &mocks.Poller{}
+	mockPoller.On("PollProperty", mock.Anything, mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			reqs := args[2].([]contracts.ReadRequest)
+			reqs[0].Failed(errors.New("read failed"))
+			reqs[1].SetResult(contracts.NewSimpleResult(int32(1)))
+		},
+	).Return(nil)
+	mockReporter := &mocks.Reporter{}
+	mockReporter.On("ReportEventContext", mock.Anything, mock.Anything).Return(nil)
+	mockStatusManager := MockStatusManager(nil)
+
+	a := &Agent{poller: mockPoller, reporter: mockReporter, StatusManager: mockStatusManager, log: logger.D}
+	resources := []models.DeviceResource{
+		{Name: "humidity", Properties: models.ResourceProperties{ValueType: common.ValueTypeInt32}},
+		{Name: "temperature", Properties: models.ResourceProperties{ValueType: common.ValueTypeInt32}},
+	}
+	a.pollDevice(context.Background(), "device-001", time.Second, resources)
+
+	mockReporter.AssertNumberOfCalls(t, "ReportEventContext", 1)
+	values := mockReporter.Calls[0].Arguments[1].(*contracts.AsyncValues)
+	require.Equal(t, "device-001", values.DeviceName)
+	require.Equal(t, "poll-1s", values.SourceName)
+	require.Len(t, values.CommandValues, 1)
+	require.Equal(t, "temperature", values.CommandValues[0].DeviceResourceName)
+	mockStatusManager.(*mocks.StatusManager).AssertCalled(t, "OnHandleCommandsFailed", "device-001", int64(1))
+
+	// nothing is reported if the poll failed
+	mockPoller = &mocks.Poller{}
+	mockPoller.On("PollProperty", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("disconnected"))
+	a.poller = mockPoller
+	a.pollDevice(context.Background(), "device-001", time.Second, resources[:1])
+	mockReporter.AssertNumberOfCalls(t, "ReportEventContext", 1)
+}
+
+func TestPollingLifeCycle(t *testing.T) {
+	mockService := &sdkmocks.DeviceServiceSDK{}
+	mockService.On("Devices").Return([]models.Device{{Name: "device-001", ProfileName: "profile"}})
+	mockService.On("GetDeviceByName", mock.Anything).Return(
+		func(name string) models.Device {
+			return models.Device{Name: name, ProfileName: "profile"}
+		}, nil)
+	mockService.On("GetProfileByName", "profile").Return(models.DeviceProfile{
+		Name: "profile",
+		DeviceResources: []models.DeviceResource{{
+			Name:       "temperature",
+			Properties: models.ResourceProperties{ValueType: common.ValueTypeInt32, ReadWrite: common.ReadWrite_R},
+			Attributes: map[string]interface{}{contracts.PollIntervalKey: "20ms"},
+		}},
+	}, nil)
+	mockPoller := &mocks.Poller{}
+	mockPoller.On("PollProperty", mock.Anything, mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			for _, req := range args[2].([]contracts.ReadRequest) {
+				req.SetResult(contracts.NewSimpleResult(int32(1)))
+			}
+		},
+	).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := &Agent{
+		ctx:           ctx,
+		service:       mockService,
+		poller:        mockPoller,
+		StatusManager: MockStatusManager(make(map[string]*contracts.Device)),
+		buffer:        buffer.New(100, contracts.DropNewest, 0),
+		log:           logger.D,
+	}
+	a.reporter = a
+	a.startPolling()
+	require.Equal(t, []string{"device-001"}, a.scheduler.Devices())
+
+	require.NoError(t, a.AddDevice("device-002", nil, ""))
+	require.Equal(t, []string{"device-001", "device-002"}, a.scheduler.Devices())
+	require.Eventually(t, func() bool {
+		return a.buffer.Depth.Value() >= 4
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, a.RemoveDevice("device-001", nil))
+	require.Equal(t, []string{"device-002"}, a.scheduler.Devices())
+
+	// The locked device is not polled until it is unlocked.
+	require.NoError(t, a.UpdateDevice("device-002", nil, models.Locked))
+	require.Empty(t, a.scheduler.Devices())
+	require.NoError(t, a.AddDevice("device-003", nil, models.Locked))
+	require.Empty(t, a.scheduler.Devices())
+	require.NoError(t, a.UpdateDevice("device-002", nil, models.Unlocked))
+	require.Equal(t, []string{"device-002"}, a.scheduler.Devices())
+
+	cancel()
+	a.scheduler.Wait()
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"time"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+// retryRequests invokes the driver with the requests, and then re-invokes it with the failed ones according to
+// the RetryPolicy until they succeed or the attempts are exhausted. The error of the driver is recorded on each
+// request of the invocation without outcome, so that every request ends up with either a result or an error. The
+// error of the driver is only returned if none of the requests succeeded.
+func retryRequests[T contracts.BaseRequest](a *Agent, device *contracts.Device, reqs []T,
+	invoke func(*contracts.Device, []T) error) error {
+
+	policy := a.RetryPolicy
+	err := invoke(device, reqs)
+	failRequests(reqs, err)
+	for retry := 1; ; retry++ {
+		failed := make([]T, 0)
+		for _, req := range reqs {
+			if retry >= policy.Attempts(req) || req.Skipped() {
+				continue
+			}
+			if req.Error() != nil && policy.Retryable(req.Error()) {
+				failed = append(failed, req)
+			}
+		}
+		if len(failed) == 0 {
+			break
+		}
+
+		var backoff time.Duration
+		for _, req := range failed {
+			if b := policy.Backoff(req, retry); b > backoff {
+				backoff = b
+			}
+		}
+		a.log.Debugf("retry %d requests of device '%s' after %v, retry: %d", len(failed), device.Name, backoff, retry)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-a.context().Done():
+			timer.Stop()
+			return outcome(reqs, err)
+		case <-timer.C:
+		}
+
+		for _, req := range failed {
+			contracts.Reset(req)
+		}
+		err = invoke(device, failed)
+		failRequests(failed, err)
+	}
+	return outcome(reqs, err)
+}
+
+// failRequests records the error of the driver on the requests which have neither a result nor an error, the error
+// is classified by the kind of request if it is not an Error.
+func failRequests[T contracts.BaseRequest](reqs []T, err error) {
+	if err == nil {
+		return
+	}
+	if _, ok := contracts.KindOf(err); !ok {
+		err = contracts.NewError(failureKind(reqs), err)
+	}
+	for _, req := range reqs {
+		if !req.Skipped() && req.Error() == nil && req.Result() == nil {
+			req.Failed(err)
+		}
+	}
+}
+
+// failureKind returns the kind of the errors of driver which are not classified for the requests.
+func failureKind[T contracts.BaseRequest](reqs []T) contracts.ErrorKind {
+	if _, ok := interface{}(reqs).([]contracts.WriteRequest); ok {
+		return contracts.WriteError
+	}
+	return contracts.ReadError
+}
+
+// outcome returns the last error of the driver if none of the requests succeeded, otherwise the failed requests
+// carry their own errors.
+func outcome[T contracts.BaseRequest](reqs []T, err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, req := range reqs {
+		if !req.Skipped() && req.Error() == nil {
+			return nil
+		}
+	}
+	return err
+}
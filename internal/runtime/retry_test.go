@@ -0,0 +1,160 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces/mocks"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+func TestRetryFailedRequests(t *testing.T) {
+	attempts := make(map[string]int)
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			for _, req := range args[1].([]contracts.ReadRequest) {
+				attempts[req.Resource()]++
+				switch {
+				case req.Resource() == "auth":
+					req.Failed(contracts.NewErrorWithReason(contracts.AuthFailed, "wrong token"))
+				case attempts[req.Resource()] < 3:
+					req.Failed(contracts.NewErrorWithReason(contracts.ReadTimeout, "no response"))
+				default:
+					req.SetResult(contracts.NewSimpleResult(float32(1.0)))
+				}
+			}
+		},
+	).Return(nil)
+
+	a := &Agent{
+		driver:        mockDriver,
+		StatusManager: MockStatusManager(nil),
+		log:           logger.D,
+		RetryPolicy:   contracts.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}
+	reqs := []models.CommandRequest{
+		{DeviceResourceName: "temperature", Type: common.ValueTypeFloat32},
+		{DeviceResourceName: "auth", Type: common.ValueTypeFloat32},
+		{DeviceResourceName: "humidity", Type: common.ValueTypeFloat32, Attributes: map[string]interface{}{contracts.RetryMaxAttemptsKey: 1}},
+	}
+	responses, err := a.HandleReadCommands("device", nil, reqs)
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	require.Equal(t, 3, attempts["temperature"])
+	require.Equal(t, 1, attempts["auth"])
+	require.Equal(t, 1, attempts["humidity"])
+
+	a.StatusManager.(*mocks.StatusManager).AssertNumberOfCalls(t, "OnHandleCommandsSuccessfully", 1)
+	a.StatusManager.(*mocks.StatusManager).AssertNumberOfCalls(t, "OnHandleCommandsFailed", 2)
+}
+
+func TestRetryFailedDriver(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("WriteProperty", mock.Anything, mock.Anything).Return(
+		contracts.NewErrorWithReason(contracts.ConnectionFailed, "refused")).Times(2)
+	mockDriver.On("WriteProperty", mock.Anything, mock.Anything).Return(nil).Once()
+
+	a := &Agent{
+		driver:        mockDriver,
+		StatusManager: MockStatusManager(nil),
+		log:           logger.D,
+		RetryPolicy:   contracts.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}
+	reqs := []models.CommandRequest{{DeviceResourceName: "switch", Type: common.ValueTypeBool}}
+	err := a.HandleWriteCommands("device", nil, reqs, []*models.CommandValue{{}})
+	require.NoError(t, err)
+	mockDriver.AssertNumberOfCalls(t, "WriteProperty", 3)
+	a.StatusManager.(*mocks.StatusManager).AssertNotCalled(t, "OnHandleCommandsFailed", mock.Anything, mock.Anything)
+
+	// no retry without policy
+	mockDriver.On("WriteProperty", mock.Anything, mock.Anything).Return(
+		contracts.NewErrorWithReason(contracts.ConnectionFailed, "refused"))
+	a.RetryPolicy = contracts.RetryPolicy{}
+	err = a.HandleWriteCommands("device", nil, reqs, []*models.CommandValue{{}})
+	require.Error(t, err)
+	mockDriver.AssertNumberOfCalls(t, "WriteProperty", 4)
+}
+
+func TestRetryFailedDriverWithExhaustedRequests(t *testing.T) {
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("WriteProperty", mock.Anything, mock.Anything).Return(
+		contracts.NewErrorWithReason(contracts.ConnectionFailed, "refused")).Once()
+	mockDriver.On("WriteProperty", mock.Anything, mock.Anything).Return(nil).Once()
+
+	a := &Agent{
+		driver:        mockDriver,
+		StatusManager: MockStatusManager(nil),
+		log:           logger.D,
+		RetryPolicy:   contracts.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}
+	reqs := []models.CommandRequest{
+		{DeviceResourceName: "switch", Type: common.ValueTypeBool},
+		{DeviceResourceName: "reset", Type: common.ValueTypeBool, Attributes: map[string]interface{}{contracts.RetryMaxAttemptsKey: 1}},
+	}
+	// the request without attempts left fails with the error of driver rather than succeeds silently
+	err := a.HandleWriteCommands("device", nil, reqs, []*models.CommandValue{{}, {}})
+	require.ErrorContains(t, err, "reset")
+	require.NotContains(t, err.Error(), "switch:")
+	mockDriver.AssertNumberOfCalls(t, "WriteProperty", 2)
+	require.Len(t, mockDriver.Calls[1].Arguments[1], 1)
+	a.StatusManager.(*mocks.StatusManager).AssertNumberOfCalls(t, "OnHandleCommandsSuccessfully", 1)
+	a.StatusManager.(*mocks.StatusManager).AssertNumberOfCalls(t, "OnHandleCommandsFailed", 1)
+}
+
+func TestRetryKeepsSucceededRequests(t *testing.T) {
+	calls := 0
+	mockDriver := &mocks.Driver{}
+	mockDriver.On("ReadProperty", mock.Anything, mock.Anything).Return(
+		func(_ *contracts.Device, reqs []contracts.ReadRequest) error {
+			calls++
+			if calls > 1 {
+				return contracts.NewErrorWithReason(contracts.ConnectionFailed, "refused")
+			}
+			reqs[0].SetResult(contracts.NewSimpleResult(float32(1.0)))
+			reqs[1].Failed(contracts.NewErrorWithReason(contracts.ReadTimeout, "no response"))
+			return nil
+		})
+
+	a := &Agent{
+		driver:        mockDriver,
+		StatusManager: MockStatusManager(nil),
+		log:           logger.D,
+		RetryPolicy:   contracts.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	}
+	device := contracts.WrapDevice("device", nil)
+	reqs := []contracts.ReadRequest{
+		contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "temperature", Type: common.ValueTypeFloat32}),
+		contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "humidity", Type: common.ValueTypeFloat32}),
+	}
+	// the error of the retry only fails the retried request
+	err := a.readProperty(device, reqs)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.NoError(t, reqs[0].Error())
+	require.NotNil(t, reqs[0].Result())
+	kind, _ := contracts.KindOf(reqs[1].Error())
+	require.Equal(t, contracts.ConnectionFailed, kind)
+}
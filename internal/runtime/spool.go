@@ -0,0 +1,190 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+const (
+	DefaultDeliveryTimeout = time.Second * 5
+	// DefaultDeliveryCheckInterval is the duration for which a passed delivery check is trusted.
+	DefaultDeliveryCheckInterval = time.Second
+)
+
+// deliverValues forwards the values to the device service. If the spool is enabled, the values are spooled when
+// the delivery backend fails the check, the delivery is not finished within the DeliveryTimeout, or the values
+// spooled earlier are waiting for replay so that the order is kept. True is returned if the values are spooled.
+func (a *Agent) deliverValues(ctx context.Context, values *contracts.AsyncValues) (bool, error) {
+	if a.spool == nil {
+		return false, a.reportValues(ctx, values)
+	}
+
+	if a.spool.Len() == 0 {
+		if err := a.checkDelivery(ctx); err != nil {
+			a.log.Warnf("delivery backend is unavailable, spool the values of device '%s': %v", values.DeviceName, err)
+		} else {
+			deliverCtx, cancel := context.WithTimeout(ctx, a.deliveryTimeout())
+			err = a.reportValues(deliverCtx, values)
+			cancel()
+			if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+				return false, err
+			}
+			a.log.Warnf("delivery of device '%s' is not finished within %v, spool the values", values.DeviceName, a.deliveryTimeout())
+		}
+	}
+
+	if err := a.spool.Append(values); err != nil {
+		return false, err
+	}
+	select {
+	case a.spoolSignal <- struct{}{}:
+	default:
+	}
+	return true, nil
+}
+
+// replaySpool replays the spooled values in order once the delivery recovers.
+func (a *Agent) replaySpool(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	defer wg.Done()
+
+	for {
+		values, err := a.spool.Peek()
+		if err != nil {
+			a.log.Errorf("read the spool failed: %v", err)
+		}
+		if values == nil {
+			if !a.waitSpool(ctx, 0) {
+				return
+			}
+			continue
+		}
+
+		if err = a.checkDelivery(ctx); err != nil {
+			// the delivery backend has not recovered yet
+			if !a.waitSpool(ctx, a.deliveryTimeout()) {
+				return
+			}
+			continue
+		}
+		deliverCtx, cancel := context.WithTimeout(ctx, a.deliveryTimeout())
+		err = a.reportValues(deliverCtx, values)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			// the delivery has not recovered yet
+			if !a.waitSpool(ctx, a.deliveryTimeout()) {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			a.log.Warnf("replay the values of device '%s' failed, drop them: %v", values.DeviceName, err)
+		} else {
+			a.StatusManager.OnHandleCommandsSuccessfully(values.DeviceName, int64(len(values.CommandValues)))
+		}
+		if err = a.spool.Commit(); err != nil {
+			a.log.Errorf("commit the spool failed: %v", err)
+		}
+	}
+}
+
+// waitSpool waits for the duration, or the values appended to the spool if the duration is zero. False is
+// returned if ctx is done.
+func (a *Agent) waitSpool(ctx context.Context, duration time.Duration) bool {
+	var timeout <-chan time.Time
+	if duration > 0 {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timeout:
+	case <-a.spoolSignal:
+	}
+	return true
+}
+
+func (a *Agent) deliveryTimeout() time.Duration {
+	return utils.Ternary(a.DeliveryTimeout <= 0, DefaultDeliveryTimeout, a.DeliveryTimeout)
+}
+
+// checkDelivery checks whether the backend receiving the events of the device service is reachable. The device
+// service sends the events in background and never returns the failures, so the outages are detected by the check.
+// A passed check is trusted for DefaultDeliveryCheckInterval, so that the backend is not dialed for each value.
+func (a *Agent) checkDelivery(ctx context.Context) error {
+	if a.DeliveryCheck == nil {
+		return nil
+	}
+	a.deliveryMutex.Lock()
+	defer a.deliveryMutex.Unlock()
+	if !a.deliveryPassed.IsZero() && time.Since(a.deliveryPassed) < DefaultDeliveryCheckInterval {
+		return nil
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, a.deliveryTimeout())
+	defer cancel()
+	if err := a.DeliveryCheck(checkCtx); err != nil {
+		a.deliveryPassed = time.Time{}
+		return err
+	}
+	a.deliveryPassed = time.Now()
+	return nil
+}
+
+// deliveryBackend returns the address of the message bus or core-data receiving the events of the device service,
+// which is resolved from the env overrides of EdgeX. Empty is returned if neither of them is configured.
+func deliveryBackend() string {
+	messageQueueHost, coreDataHost := os.Getenv("MESSAGEQUEUE_HOST"), os.Getenv("CLIENTS_CORE_DATA_HOST")
+	// the message bus is used unless disabled explicitly or core-data is the only one configured
+	if utils.GetBoolEnv("DEVICE_USEMESSAGEBUS", messageQueueHost != "" || coreDataHost == "") {
+		if messageQueueHost == "" {
+			return ""
+		}
+		return net.JoinHostPort(messageQueueHost, strconv.FormatInt(utils.GetIntEnv("MESSAGEQUEUE_PORT", 6379), 10))
+	}
+	if coreDataHost == "" {
+		return ""
+	}
+	return net.JoinHostPort(coreDataHost, strconv.FormatInt(utils.GetIntEnv("CLIENTS_CORE_DATA_PORT", 59880), 10))
+}
+
+// dialCheck returns the check which dials the address of the delivery backend.
+func dialCheck(address string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+		if err != nil {
+			return fmt.Errorf("delivery backend '%s' is unreachable: %w", address, err)
+		}
+		return conn.Close()
+	}
+}
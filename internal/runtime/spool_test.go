@@ -0,0 +1,176 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/buffer"
+	"github.com/volcengine/vei-driver-sdk-go/internal/spool"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+func TestSpoolAsyncValues(t *testing.T) {
+	s, err := spool.Open(spool.Options{Dir: t.TempDir()})
+	require.NoError(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	asyncCh := make(chan *models.AsyncValues)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := &Agent{
+		asyncCh:         asyncCh,
+		log:             logger.D,
+		ctx:             ctx,
+		stop:            cancel,
+		wg:              &sync.WaitGroup{},
+		async:           make(chan *contracts.AsyncValues),
+		buffer:          buffer.New(10, contracts.Block, 0),
+		spool:           s,
+		spoolSignal:     make(chan struct{}, 1),
+		StatusManager:   MockStatusManager(nil),
+		DeliveryTimeout: time.Millisecond * 20,
+	}
+	go a.HandleAsyncResults(ctx, a.wg)
+
+	// nobody receives the values during the outage
+	for i := 0; i < 5; i++ {
+		cv := &models.CommandValue{DeviceResourceName: "temperature", Type: "Int32", Value: int32(i), Origin: int64(i + 1)}
+		require.NoError(t, a.ReportEvent(&contracts.AsyncValues{
+			DeviceName: "device-001", SourceName: fmt.Sprintf("source-%d", i), CommandValues: []*models.CommandValue{cv},
+		}))
+	}
+	require.Eventually(t, func() bool { return s.Len() == 5 }, time.Second, time.Millisecond*10)
+
+	// the values are replayed in order once the delivery recovers
+	for i := 0; i < 5; i++ {
+		values := <-asyncCh
+		require.Equal(t, fmt.Sprintf("source-%d", i), values.SourceName)
+		require.Equal(t, int64(i+1), values.CommandValues[0].Origin)
+	}
+	require.Eventually(t, func() bool { return s.Len() == 0 }, time.Second, time.Millisecond*10)
+
+	// delivered directly after the spool is drained
+	require.NoError(t, a.ReportEvent(&contracts.AsyncValues{DeviceName: "device-001", SourceName: "source-5"}))
+	require.Equal(t, "source-5", (<-asyncCh).SourceName)
+	require.Equal(t, 0, s.Len())
+}
+
+func TestSpoolAsyncValuesDuringBackendOutage(t *testing.T) {
+	s, err := spool.Open(spool.Options{Dir: t.TempDir()})
+	require.NoError(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	// the device service receives the values at once no matter whether the backend is reachable
+	asyncCh := make(chan *models.AsyncValues)
+	var received []string
+	var receivedMutex sync.Mutex
+	go func() {
+		for values := range asyncCh {
+			receivedMutex.Lock()
+			received = append(received, values.SourceName)
+			receivedMutex.Unlock()
+		}
+	}()
+	defer close(asyncCh)
+	receivedSources := func() []string {
+		receivedMutex.Lock()
+		defer receivedMutex.Unlock()
+		return append([]string{}, received...)
+	}
+
+	var down int32 = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := &Agent{
+		asyncCh:         asyncCh,
+		log:             logger.D,
+		ctx:             ctx,
+		stop:            cancel,
+		wg:              &sync.WaitGroup{},
+		async:           make(chan *contracts.AsyncValues),
+		buffer:          buffer.New(10, contracts.Block, 0),
+		spool:           s,
+		spoolSignal:     make(chan struct{}, 1),
+		StatusManager:   MockStatusManager(nil),
+		DeliveryTimeout: time.Millisecond * 20,
+		DeliveryCheck: func(context.Context) error {
+			if atomic.LoadInt32(&down) == 1 {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	}
+	go a.HandleAsyncResults(ctx, a.wg)
+
+	// the values are spooled rather than sent while the backend is down
+	for i := 0; i < 5; i++ {
+		cv := &models.CommandValue{DeviceResourceName: "temperature", Type: "Int32", Value: int32(i)}
+		require.NoError(t, a.ReportEvent(&contracts.AsyncValues{
+			DeviceName: "device-001", SourceName: fmt.Sprintf("source-%d", i), CommandValues: []*models.CommandValue{cv},
+		}))
+	}
+	require.Eventually(t, func() bool { return s.Len() == 5 }, time.Second, time.Millisecond*10)
+	time.Sleep(time.Millisecond * 50)
+	require.Empty(t, receivedSources())
+
+	// the values are replayed in order once the backend recovers
+	atomic.StoreInt32(&down, 0)
+	require.Eventually(t, func() bool { return s.Len() == 0 }, time.Second, time.Millisecond*10)
+	require.NoError(t, a.ReportEvent(&contracts.AsyncValues{DeviceName: "device-001", SourceName: "source-5"}))
+	require.Eventually(t, func() bool { return len(receivedSources()) == 6 }, time.Second, time.Millisecond*10)
+	for i, source := range receivedSources() {
+		require.Equal(t, fmt.Sprintf("source-%d", i), source)
+	}
+}
+
+func TestDeliveryBackend(t *testing.T) {
+	t.Setenv("DEVICE_USEMESSAGEBUS", "")
+	t.Setenv("MESSAGEQUEUE_HOST", "")
+	t.Setenv("CLIENTS_CORE_DATA_HOST", "")
+	require.Equal(t, "", deliveryBackend())
+
+	t.Setenv("CLIENTS_CORE_DATA_HOST", "edgex-core-data")
+	require.Equal(t, "edgex-core-data:59880", deliveryBackend())
+	t.Setenv("MESSAGEQUEUE_HOST", "edgex-redis")
+	require.Equal(t, "edgex-redis:6379", deliveryBackend())
+	t.Setenv("DEVICE_USEMESSAGEBUS", "false")
+	t.Setenv("CLIENTS_CORE_DATA_PORT", "8080")
+	require.Equal(t, "edgex-core-data:8080", deliveryBackend())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	check := dialCheck(listener.Addr().String())
+	require.NoError(t, check(context.Background()))
+	require.NoError(t, listener.Close())
+	require.Error(t, check(context.Background()))
+}
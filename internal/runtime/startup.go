@@ -43,9 +43,30 @@ func Startup(name string, version string, proto interface{}, opts ...Option) {
 		os.Exit(1)
 	}
 
+	if ctxDriver, ok := proto.(interfaces.ContextDriver); ok {
+		agent.ctxDriver = ctxDriver
+	}
 	if handler, ok := proto.(interfaces.DeviceHandler); ok {
 		agent.handler = handler
 	}
+	if admin, ok := proto.(interfaces.AdminStateHandler); ok {
+		agent.admin = admin
+	}
+	if subscriber, ok := proto.(interfaces.EventSubscriber); ok {
+		agent.subscriber = subscriber
+	}
+	if poller, ok := proto.(interfaces.Poller); ok {
+		agent.poller = poller
+	}
+	if health, ok := proto.(interfaces.HealthChecker); ok {
+		agent.health = health
+	}
+	if readiness, ok := proto.(interfaces.ReadinessChecker); ok {
+		agent.readiness = readiness
+	}
+	if watcher, ok := proto.(interfaces.ConfigWatcher); ok {
+		agent.watcher = watcher
+	}
 	if discovery, ok := proto.(interfaces.Discovery); ok {
 		agent.discovery = discovery
 	}
@@ -63,6 +84,11 @@ func StatusManager() interfaces.StatusManager {
 	return agent.StatusManager
 }
 
+// DriverConfigs returns a copy of the driver configs in use, which are updated once the Driver section changes.
+func DriverConfigs() map[string]string {
+	return agent.DriverConfigs()
+}
+
 func Reporter() interfaces.Reporter {
 	return agent.reporter
 }
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+// resourceTransform returns the transform of the resource in the request, the properties and the attributes of the
+// resource are taken into account only if TransformProperties and TransformAttributes are enabled respectively.
+func (a *Agent) resourceTransform(deviceName string, req contracts.BaseRequest) (*contracts.Transform, error) {
+	var properties models.ResourceProperties
+	if a.TransformProperties && a.service != nil {
+		if resource, ok := a.service.DeviceResource(deviceName, req.Native().DeviceResourceName); ok {
+			properties = resource.Properties
+		}
+	}
+	var attributes map[string]interface{}
+	if a.TransformAttributes {
+		attributes = req.Attributes()
+	} else if enabled, ok := req.Attributes()[contracts.TransformKey]; ok {
+		// The resource can still opt out of the transform of its properties.
+		attributes = map[string]interface{}{contracts.TransformKey: enabled}
+	}
+	return contracts.NewTransform(properties, attributes)
+}
+
+// transformResults sets the transform of the resources to the SimpleResult of the reads, so that the raw values
+// read by driver are transformed before they are cast. The other kinds of results are left as they are.
+func (a *Agent) transformResults(deviceName string, reqs []contracts.ReadRequest) {
+	for _, req := range reqs {
+		result, ok := req.Result().(*contracts.SimpleResult)
+		if !ok || req.Error() != nil {
+			continue
+		}
+		transform, err := a.resourceTransform(deviceName, req)
+		if err != nil {
+			req.Failed(err)
+			continue
+		}
+		result.WithTransform(transform)
+	}
+}
+
+// transformParam returns the write request whose parameter is transformed back into the raw value, the request is
+// returned unchanged if there is no transform for the resource.
+func (a *Agent) transformParam(deviceName string, req contracts.WriteRequest) (contracts.WriteRequest, error) {
+	param := req.Param()
+	if param == nil || !contracts.ValueType(param.Type).IsNumeric() {
+		return req, nil
+	}
+	transform, err := a.resourceTransform(deviceName, req)
+	if err != nil || transform == nil {
+		return req, err
+	}
+	raw, err := transform.Write(param.Value, contracts.ValueType(param.Type))
+	if err == nil {
+		param, err = utils.CastCommandValue(param.DeviceResourceName, param.Type, raw)
+	}
+	if err != nil {
+		return req, contracts.NewErrorWithReason(contracts.ParameterParseFailed, fmt.Sprintf("resource '%s': %v", req.Native().DeviceResourceName, err))
+	}
+	param.Origin, param.Tags = req.Param().Origin, req.Param().Tags
+	return contracts.NewWriteRequest(*req.Native(), param), nil
+}
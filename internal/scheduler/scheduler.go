@@ -0,0 +1,168 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+const (
+	// DefaultJitter is the default ratio of the random jitter to the interval.
+	DefaultJitter = 0.1
+)
+
+// Task polls the resources of the device which are grouped by the interval.
+type Task func(ctx context.Context, deviceName string, interval time.Duration, resources []models.DeviceResource)
+
+// Group groups the readable properties by the attribute 'pollInterval', the resources without a valid interval
+// are not polled.
+func Group(resources []models.DeviceResource) map[time.Duration][]models.DeviceResource {
+	groups := make(map[time.Duration][]models.DeviceResource)
+	for _, resource := range resources {
+		if resource.Properties.ReadWrite == common.ReadWrite_W {
+			continue
+		}
+		if !contracts.IsProperty(sdkmodels.CommandRequest{Attributes: resource.Attributes}) {
+			continue
+		}
+		interval, ok := contracts.GetResourceDuration(resource.Attributes, contracts.PollIntervalKey)
+		if !ok || interval <= 0 {
+			continue
+		}
+		groups[interval] = append(groups[interval], resource)
+	}
+	return groups
+}
+
+// Scheduler polls the resources of each device periodically, the first poll of each group is delayed randomly
+// within the interval and the following ones are jittered, so that the devices are not polled all at once.
+type Scheduler struct {
+	ctx    context.Context
+	task   Task
+	jitter float64
+	jobs   map[string]context.CancelFunc
+	mutex  sync.Mutex
+	wg     sync.WaitGroup
+}
+
+// New creates the scheduler which stops once ctx is done, the jitter is the ratio to the interval and
+// DefaultJitter is used if it is not in (0, 1].
+func New(ctx context.Context, task Task, jitter float64) *Scheduler {
+	if jitter <= 0 || jitter > 1 {
+		jitter = DefaultJitter
+	}
+	return &Scheduler{
+		ctx:    ctx,
+		task:   task,
+		jitter: jitter,
+		jobs:   make(map[string]context.CancelFunc),
+		mutex:  sync.Mutex{},
+	}
+}
+
+// Schedule polls the groups of resources of the device, the groups scheduled before are replaced.
+func (s *Scheduler) Schedule(deviceName string, groups map[time.Duration][]models.DeviceResource) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if cancel, ok := s.jobs[deviceName]; ok {
+		cancel()
+		delete(s.jobs, deviceName)
+	}
+	if len(groups) == 0 || s.ctx.Err() != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.jobs[deviceName] = cancel
+	for interval, resources := range groups {
+		if interval <= 0 || len(resources) == 0 {
+			continue
+		}
+		sort.Slice(resources, func(i, j int) bool {
+			return resources[i].Name < resources[j].Name
+		})
+		s.wg.Add(1)
+		go s.run(ctx, deviceName, interval, resources)
+	}
+}
+
+// Unschedule stops polling the device.
+func (s *Scheduler) Unschedule(deviceName string) {
+	s.Schedule(deviceName, nil)
+}
+
+// Devices returns the names of the devices being polled.
+func (s *Scheduler) Devices() []string {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Wait waits for the polls in progress to finish once the scheduler stops.
+func (s *Scheduler) Wait() {
+	if s == nil {
+		return
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, deviceName string, interval time.Duration, resources []models.DeviceResource) {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(interval))))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		s.task(ctx, deviceName, interval, resources)
+		timer.Reset(s.next(interval))
+	}
+}
+
+// next returns the interval jittered randomly within the ratio.
+func (s *Scheduler) next(interval time.Duration) time.Duration {
+	delta := int64(float64(interval) * s.jitter)
+	if delta <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(2*delta+1)-delta)
+}
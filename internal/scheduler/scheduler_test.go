@@ -0,0 +1,128 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+func newResource(name string, readWrite string, attributes map[string]interface{}) models.DeviceResource {
+	return models.DeviceResource{Name: name, Properties: models.ResourceProperties{ReadWrite: readWrite}, Attributes: attributes}
+}
+
+func TestGroup(t *testing.T) {
+	groups := Group([]models.DeviceResource{
+		newResource("temperature", common.ReadWrite_R, map[string]interface{}{contracts.PollIntervalKey: "1s"}),
+		newResource("humidity", common.ReadWrite_RW, map[string]interface{}{contracts.PollIntervalKey: 1000}),
+		newResource("voltage", common.ReadWrite_R, map[string]interface{}{contracts.PollIntervalKey: "1m"}),
+		newResource("switch", common.ReadWrite_W, map[string]interface{}{contracts.PollIntervalKey: "1s"}),
+		newResource("reboot", common.ReadWrite_R, map[string]interface{}{contracts.PollIntervalKey: "1s", contracts.CategoryKey: "service"}),
+		newResource("status", common.ReadWrite_R, map[string]interface{}{contracts.PollIntervalKey: "invalid"}),
+		newResource("model", common.ReadWrite_R, nil),
+	})
+	require.Len(t, groups, 2)
+	require.Len(t, groups[time.Second], 2)
+	require.Len(t, groups[time.Minute], 1)
+}
+
+type recorder struct {
+	polls map[string]int
+	mutex sync.Mutex
+}
+
+func (r *recorder) task(_ context.Context, deviceName string, interval time.Duration, resources []models.DeviceResource) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, resource := range resources {
+		r.polls[deviceName+"/"+resource.Name+"/"+interval.String()]++
+	}
+}
+
+func (r *recorder) count(key string) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.polls[key]
+}
+
+func TestScheduler(t *testing.T) {
+	r := &recorder{polls: make(map[string]int)}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := New(ctx, r.task, 0)
+
+	interval := 20 * time.Millisecond
+	s.Schedule("device-001", map[time.Duration][]models.DeviceResource{
+		interval:  {newResource("temperature", common.ReadWrite_R, nil)},
+		time.Hour: {newResource("voltage", common.ReadWrite_R, nil)},
+	})
+	s.Schedule("device-002", map[time.Duration][]models.DeviceResource{
+		interval: {newResource("temperature", common.ReadWrite_R, nil)},
+	})
+	require.Equal(t, []string{"device-001", "device-002"}, s.Devices())
+
+	require.Eventually(t, func() bool {
+		return r.count("device-001/temperature/20ms") >= 3 && r.count("device-002/temperature/20ms") >= 3
+	}, time.Second, interval)
+	require.Zero(t, r.count("device-001/voltage/1h0m0s"))
+
+	// the device stops being polled once unscheduled
+	s.Unschedule("device-002")
+	require.Equal(t, []string{"device-001"}, s.Devices())
+	time.Sleep(interval)
+	polled := r.count("device-002/temperature/20ms")
+	time.Sleep(3 * interval)
+	require.Equal(t, polled, r.count("device-002/temperature/20ms"))
+
+	// the groups are replaced when scheduled again
+	s.Schedule("device-001", map[time.Duration][]models.DeviceResource{
+		interval: {newResource("humidity", common.ReadWrite_R, nil)},
+	})
+	require.Eventually(t, func() bool {
+		return r.count("device-001/humidity/20ms") >= 1
+	}, time.Second, interval)
+
+	cancel()
+	s.Wait()
+	s.Schedule("device-003", map[time.Duration][]models.DeviceResource{
+		interval: {newResource("temperature", common.ReadWrite_R, nil)},
+	})
+	require.NotContains(t, s.Devices(), "device-003")
+
+	var nilScheduler *Scheduler
+	nilScheduler.Unschedule("device-001")
+	nilScheduler.Wait()
+}
+
+func TestScheduler_Jitter(t *testing.T) {
+	s := New(context.Background(), nil, 0.5)
+	for i := 0; i < 100; i++ {
+		next := s.next(time.Second)
+		require.GreaterOrEqual(t, next, 500*time.Millisecond)
+		require.LessOrEqual(t, next, 1500*time.Millisecond)
+	}
+
+	s = New(context.Background(), nil, 2)
+	require.Equal(t, DefaultJitter, s.jitter)
+}
@@ -0,0 +1,452 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spool
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+const (
+	DefaultMaxSegmentSize = 4 << 20
+	DefaultMaxSize        = 256 << 20
+
+	segmentSuffix = ".seg"
+	cursorFile    = "cursor"
+	headerSize    = 8 // the length and the checksum of record
+)
+
+// ErrCorrupted is returned when the record read from the segment is broken.
+var ErrCorrupted = errors.New("the record of spool is corrupted")
+
+func init() {
+	// the types of object and array values which are not registered by gob
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// Options is the options of spool, the spool is disabled if no directory is specified.
+type Options struct {
+	// the directory to store the segments.
+	Dir string
+	// the maximum size of a segment in bytes, a new segment is created once exceeded.
+	MaxSegmentSize int64
+	// the maximum size of all the segments in bytes, the oldest segments are dropped once exceeded.
+	MaxSize int64
+	// the maximum age of the segments, the segments not written for longer are dropped. Zero means no limit.
+	MaxAge time.Duration
+}
+
+// Spool is a durable FIFO queue of async values, which is stored in segment files in order. The values are
+// read by Peek and removed by Commit once delivered, the position of the next value is persisted in the cursor
+// file, so the values are delivered at least once across restarts.
+type Spool struct {
+	options  Options
+	segments []int64 // the sequence numbers of segments in order, the last one is being written
+	sizes    map[int64]int64
+	writer   *os.File
+	reader   *os.File
+	readSeq  int64
+	readOff  int64
+	peeked   *contracts.AsyncValues
+	peekSize int64
+	count    int
+	mutex    sync.Mutex
+
+	Size    metrics.Gauge   // the size of all the segments in bytes
+	Pending metrics.Gauge   // the number of values waiting for replay
+	Drops   metrics.Counter // the number of values dropped due to the size or age limit
+}
+
+// Open opens the spool in the directory, the values left by the last run are recovered.
+func Open(options Options) (*Spool, error) {
+	if options.MaxSegmentSize <= 0 {
+		options.MaxSegmentSize = DefaultMaxSegmentSize
+	}
+	if options.MaxSize <= 0 {
+		options.MaxSize = DefaultMaxSize
+	}
+	if err := os.MkdirAll(options.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &Spool{
+		options: options,
+		sizes:   make(map[int64]int64),
+		Size:    metrics.NewGauge(),
+		Pending: metrics.NewGauge(),
+		Drops:   metrics.NewCounter(),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load scans the segments and the cursor left in the directory.
+func (s *Spool) load() error {
+	entries, err := os.ReadDir(s.options.Dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseInt(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		s.segments = append(s.segments, seq)
+		s.sizes[seq] = info.Size()
+	}
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i] < s.segments[j] })
+
+	if data, err := os.ReadFile(s.path(cursorFile)); err == nil {
+		_, _ = fmt.Sscanf(string(data), "%d %d", &s.readSeq, &s.readOff)
+	}
+	// the segments before the cursor have been delivered
+	for len(s.segments) > 0 && s.segments[0] < s.readSeq {
+		s.removeSegment(s.segments[0])
+	}
+	if len(s.segments) == 0 || s.segments[0] != s.readSeq {
+		s.readOff = 0
+	}
+	if len(s.segments) > 0 {
+		s.readSeq = s.segments[0]
+	}
+
+	if err = s.countPending(); err != nil {
+		return err
+	}
+	return s.openWriter(s.lastSeq() + 1)
+}
+
+// countPending counts the values from the cursor to the end, the broken tail of the last run is truncated.
+func (s *Spool) countPending() error {
+	for i, seq := range s.segments {
+		offset := utils.Ternary(seq == s.readSeq, s.readOff, 0)
+		file, err := os.Open(s.segmentPath(seq))
+		if err != nil {
+			return err
+		}
+		valid, count := scan(file, offset)
+		_ = file.Close()
+		s.count += count
+		if valid < s.sizes[seq] && i == len(s.segments)-1 {
+			if err = os.Truncate(s.segmentPath(seq), valid); err != nil {
+				return err
+			}
+			s.sizes[seq] = valid
+		}
+	}
+	s.updateMetrics()
+	return nil
+}
+
+// Append appends the values to the end of the spool.
+func (s *Spool) Append(values *contracts.AsyncValues) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(values); err != nil {
+		return err
+	}
+	record := make([]byte, headerSize+buf.Len())
+	binary.BigEndian.PutUint32(record[0:4], uint32(buf.Len()))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(buf.Bytes()))
+	copy(record[headerSize:], buf.Bytes())
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	seq := s.lastSeq()
+	if s.sizes[seq] > 0 && s.sizes[seq]+int64(len(record)) > s.options.MaxSegmentSize {
+		if err := s.openWriter(seq + 1); err != nil {
+			return err
+		}
+		seq++
+	}
+	if _, err := s.writer.Write(record); err != nil {
+		return err
+	}
+	s.sizes[seq] += int64(len(record))
+	s.count++
+	s.enforceLimits()
+	s.updateMetrics()
+	return nil
+}
+
+// Peek returns the oldest values without removing them, nil is returned if the spool is empty.
+func (s *Spool) Peek() (*contracts.AsyncValues, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.peeked != nil {
+		return s.peeked, nil
+	}
+	s.enforceLimits()
+	for {
+		if s.count == 0 {
+			return nil, nil
+		}
+		if s.readOff >= s.sizes[s.readSeq] {
+			if s.readSeq == s.lastSeq() {
+				return nil, nil
+			}
+			// the segment has been read through
+			s.closeReader()
+			s.removeSegment(s.readSeq)
+			s.readSeq, s.readOff = s.segments[0], 0
+			continue
+		}
+		if s.reader == nil {
+			file, err := os.Open(s.segmentPath(s.readSeq))
+			if err != nil {
+				return nil, err
+			}
+			s.reader = file
+		}
+		values, size, err := readRecord(s.reader, s.readOff)
+		if err != nil {
+			// skip the rest of the broken segment, and count the values left in the following ones
+			s.readOff = s.sizes[s.readSeq]
+			s.Drops.Inc(1)
+			s.recount()
+			continue
+		}
+		s.peeked, s.peekSize = values, size
+		return values, nil
+	}
+}
+
+// Commit removes the values returned by the last Peek.
+func (s *Spool) Commit() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.peeked == nil {
+		return nil
+	}
+	s.peeked = nil
+	s.readOff += s.peekSize
+	s.count--
+	s.updateMetrics()
+	return os.WriteFile(s.path(cursorFile), []byte(fmt.Sprintf("%d %d", s.readSeq, s.readOff)), 0644)
+}
+
+// Len returns the number of values waiting for replay.
+func (s *Spool) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.count
+}
+
+// Close closes the files of spool.
+func (s *Spool) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closeReader()
+	if s.writer != nil {
+		err := s.writer.Close()
+		s.writer = nil
+		return err
+	}
+	return nil
+}
+
+// enforceLimits drops the oldest segments exceeding the size or age limit, the one being written is kept.
+func (s *Spool) enforceLimits() {
+	for len(s.segments) > 1 {
+		seq := s.segments[0]
+		expired := false
+		if s.options.MaxAge > 0 {
+			if info, err := os.Stat(s.segmentPath(seq)); err == nil && time.Since(info.ModTime()) > s.options.MaxAge {
+				expired = true
+			}
+		}
+		if !expired && s.totalSize() <= s.options.MaxSize {
+			return
+		}
+
+		if s.readSeq == seq {
+			s.closeReader()
+			s.peeked = nil
+			file, err := os.Open(s.segmentPath(seq))
+			if err == nil {
+				dropped := countRest(file, s.readOff)
+				_ = file.Close()
+				s.count -= dropped
+				s.Drops.Inc(int64(dropped))
+			}
+			s.readSeq, s.readOff = s.segments[1], 0
+		}
+		s.removeSegment(seq)
+	}
+}
+
+func (s *Spool) openWriter(seq int64) error {
+	if s.writer != nil {
+		if err := s.writer.Close(); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(s.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	s.writer = file
+	if _, exist := s.sizes[seq]; !exist {
+		s.segments = append(s.segments, seq)
+		s.sizes[seq] = 0
+	}
+	if len(s.segments) == 1 {
+		s.readSeq = seq
+	}
+	return nil
+}
+
+func (s *Spool) closeReader() {
+	if s.reader != nil {
+		_ = s.reader.Close()
+		s.reader = nil
+	}
+}
+
+func (s *Spool) removeSegment(seq int64) {
+	_ = os.Remove(s.segmentPath(seq))
+	delete(s.sizes, seq)
+	for i, v := range s.segments {
+		if v == seq {
+			s.segments = append(s.segments[:i], s.segments[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *Spool) lastSeq() int64 {
+	if len(s.segments) == 0 {
+		return 0
+	}
+	return s.segments[len(s.segments)-1]
+}
+
+func (s *Spool) totalSize() int64 {
+	var total int64
+	for _, size := range s.sizes {
+		total += size
+	}
+	return total
+}
+
+func (s *Spool) updateMetrics() {
+	s.Size.Update(s.totalSize())
+	s.Pending.Update(int64(s.count))
+}
+
+func (s *Spool) path(name string) string {
+	return filepath.Join(s.options.Dir, name)
+}
+
+func (s *Spool) segmentPath(seq int64) string {
+	return s.path(fmt.Sprintf("%020d%s", seq, segmentSuffix))
+}
+
+// readRecord reads the record at the offset, and returns the values and the size of record.
+func readRecord(file *os.File, offset int64) (*contracts.AsyncValues, int64, error) {
+	header := make([]byte, headerSize)
+	if _, err := file.ReadAt(header, offset); err != nil {
+		return nil, 0, ErrCorrupted
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	data := make([]byte, length)
+	if _, err := file.ReadAt(data, offset+headerSize); err != nil {
+		return nil, 0, ErrCorrupted
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, 0, ErrCorrupted
+	}
+	values := &contracts.AsyncValues{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(values); err != nil {
+		return nil, 0, ErrCorrupted
+	}
+	return values, int64(headerSize + length), nil
+}
+
+// scan returns the end of the valid records from the offset and the number of them.
+func scan(file *os.File, offset int64) (int64, int) {
+	reader := bufio.NewReader(io.NewSectionReader(file, offset, 1<<62))
+	header := make([]byte, headerSize)
+	count := 0
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return offset, count
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		data := make([]byte, length)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return offset, count
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+			return offset, count
+		}
+		offset += int64(headerSize + length)
+		count++
+	}
+}
+
+// recount counts the values from the cursor to the end again.
+func (s *Spool) recount() {
+	s.count = 0
+	for _, seq := range s.segments {
+		file, err := os.Open(s.segmentPath(seq))
+		if err != nil {
+			continue
+		}
+		s.count += countRest(file, utils.Ternary(seq == s.readSeq, s.readOff, 0))
+		_ = file.Close()
+	}
+	s.updateMetrics()
+}
+
+// countRest returns the number of valid records from the offset.
+func countRest(file *os.File, offset int64) int {
+	_, count := scan(file, offset)
+	return count
+}
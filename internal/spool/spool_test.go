@@ -0,0 +1,145 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+func newValues(t *testing.T, i int) *contracts.AsyncValues {
+	cv, err := models.NewCommandValueWithOrigin("temperature", common.ValueTypeInt32, int32(i), int64(1000+i))
+	require.NoError(t, err)
+	obj, err := models.NewCommandValueWithOrigin("object", common.ValueTypeObject, map[string]interface{}{"i": i}, int64(1000+i))
+	require.NoError(t, err)
+	return &contracts.AsyncValues{
+		DeviceName:    "device",
+		SourceName:    fmt.Sprintf("source-%d", i),
+		CommandValues: []*models.CommandValue{cv, obj},
+	}
+}
+
+func replay(t *testing.T, s *Spool) []string {
+	sources := make([]string, 0)
+	for {
+		values, err := s.Peek()
+		require.NoError(t, err)
+		if values == nil {
+			return sources
+		}
+		sources = append(sources, values.SourceName)
+		require.NoError(t, s.Commit())
+	}
+}
+
+func TestSpool(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Options{Dir: dir})
+	require.NoError(t, err)
+
+	values, err := s.Peek()
+	require.NoError(t, err)
+	require.Nil(t, values)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Append(newValues(t, i)))
+	}
+	require.Equal(t, 3, s.Len())
+	require.Equal(t, int64(3), s.Pending.Value())
+
+	// peek again without commit returns the same values
+	values, err = s.Peek()
+	require.NoError(t, err)
+	again, err := s.Peek()
+	require.NoError(t, err)
+	require.Same(t, values, again)
+	require.Equal(t, "source-0", values.SourceName)
+	require.Equal(t, int32(0), values.CommandValues[0].Value)
+	require.Equal(t, int64(1000), values.CommandValues[0].Origin)
+	require.Equal(t, map[string]interface{}{"i": 0}, values.CommandValues[1].Value)
+	require.NoError(t, s.Commit())
+	require.NoError(t, s.Close())
+
+	// the values left are recovered after reopened
+	s, err = Open(Options{Dir: dir})
+	require.NoError(t, err)
+	require.Equal(t, 2, s.Len())
+	require.NoError(t, s.Append(newValues(t, 3)))
+	require.Equal(t, []string{"source-1", "source-2", "source-3"}, replay(t, s))
+	require.Equal(t, 0, s.Len())
+	require.NoError(t, s.Close())
+}
+
+func TestSpool_Limits(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Options{Dir: dir, MaxSegmentSize: 1, MaxSize: 1})
+	require.NoError(t, err)
+
+	// each segment holds one record, only the latest segment is kept
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Append(newValues(t, i)))
+	}
+	require.Equal(t, 1, s.Len())
+	require.Equal(t, int64(4), s.Drops.Count())
+	require.Equal(t, []string{"source-4"}, replay(t, s))
+	require.NoError(t, s.Close())
+
+	dir = t.TempDir()
+	s, err = Open(Options{Dir: dir, MaxSegmentSize: 1, MaxAge: time.Minute})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.Append(newValues(t, i)))
+	}
+	expired := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(s.segmentPath(s.segments[0]), expired, expired))
+	require.Equal(t, []string{"source-1", "source-2"}, replay(t, s))
+	require.Equal(t, int64(1), s.Drops.Count())
+	require.NoError(t, s.Close())
+}
+
+func TestSpool_Corrupted(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(Options{Dir: dir})
+	require.NoError(t, err)
+	require.NoError(t, s.Append(newValues(t, 0)))
+	require.NoError(t, s.Append(newValues(t, 1)))
+	require.NoError(t, s.Close())
+
+	// the broken tail written by the last run is truncated
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	info, err := os.Stat(matches[0])
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(matches[0], info.Size()-1))
+
+	s, err = Open(Options{Dir: dir})
+	require.NoError(t, err)
+	require.Equal(t, 1, s.Len())
+	require.NoError(t, s.Append(newValues(t, 2)))
+	require.Equal(t, []string{"source-0", "source-2"}, replay(t, s))
+	require.NoError(t, s.Close())
+}
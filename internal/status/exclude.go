@@ -0,0 +1,64 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces"
+)
+
+// excludedManager ignores the status of the devices excluded from the status reporting, such as the devices locked
+// by the operator. The devices are added to and removed from the wrapped manager by the caller when the exclusion
+// changes.
+type excludedManager struct {
+	interfaces.StatusManager
+	excluded func(deviceName string) bool
+}
+
+// Exclude wraps the StatusManager to ignore the commands and status of the devices which are excluded.
+func Exclude(manager interfaces.StatusManager, excluded func(deviceName string) bool) interfaces.StatusManager {
+	return &excludedManager{StatusManager: manager, excluded: excluded}
+}
+
+func (m *excludedManager) OnHandleCommandsFailed(deviceName string, n int64) {
+	if !m.excluded(deviceName) {
+		m.StatusManager.OnHandleCommandsFailed(deviceName, n)
+	}
+}
+
+func (m *excludedManager) OnHandleCommandsSuccessfully(deviceName string, n int64) {
+	if !m.excluded(deviceName) {
+		m.StatusManager.OnHandleCommandsSuccessfully(deviceName, n)
+	}
+}
+
+func (m *excludedManager) SetDeviceOffline(deviceName string, reason string) {
+	if !m.excluded(deviceName) {
+		m.StatusManager.SetDeviceOffline(deviceName, reason)
+	}
+}
+
+func (m *excludedManager) SetDeviceOnline(deviceName string) {
+	if !m.excluded(deviceName) {
+		m.StatusManager.SetDeviceOnline(deviceName)
+	}
+}
+
+func (m *excludedManager) UpdateDeviceStatus(deviceName string, status string, reason string) {
+	if !m.excluded(deviceName) {
+		m.StatusManager.UpdateDeviceStatus(deviceName, status, reason)
+	}
+}
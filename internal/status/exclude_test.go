@@ -0,0 +1,47 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"testing"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces/mocks"
+)
+
+func TestExclude(t *testing.T) {
+	manager := &mocks.StatusManager{}
+	manager.On("OnAddDevice", "locked").Return().Once()
+	manager.On("OnRemoveDevice", "locked").Return().Once()
+	manager.On("OnHandleCommandsFailed", "unlocked", int64(1)).Return().Once()
+	manager.On("OnHandleCommandsSuccessfully", "unlocked", int64(2)).Return().Once()
+	manager.On("SetDeviceOffline", "unlocked", "reason").Return().Once()
+	manager.On("SetDeviceOnline", "unlocked").Return().Once()
+	manager.On("UpdateDeviceStatus", "unlocked", "Up", "").Return().Once()
+
+	excluded := Exclude(manager, func(deviceName string) bool { return deviceName == "locked" })
+	// The devices are always added and removed by the caller.
+	excluded.OnAddDevice("locked")
+	excluded.OnRemoveDevice("locked")
+	for _, deviceName := range []string{"locked", "unlocked"} {
+		excluded.OnHandleCommandsFailed(deviceName, 1)
+		excluded.OnHandleCommandsSuccessfully(deviceName, 2)
+		excluded.SetDeviceOffline(deviceName, "reason")
+		excluded.SetDeviceOnline(deviceName)
+		excluded.UpdateDeviceStatus(deviceName, "Up", "")
+	}
+	manager.AssertExpectations(t)
+}
@@ -37,6 +37,9 @@ import (
 var (
 	client   interfaces.DeviceStatusClient
 	interval int64
+
+	reportErr   error // the error of the last report
+	reportMutex sync.Mutex
 )
 
 func init() {
@@ -200,7 +203,22 @@ func (md *ManagedDevice) report() {
 		return
 	}
 
-	if _, err := client.Update(context.Background(), requests.NewUpdateDeviceStatusRequest(request)); err != nil {
+	_, err := client.Update(context.Background(), requests.NewUpdateDeviceStatusRequest(request))
+	if err != nil {
 		logger.D.Warnf("[StatusManager] update device '%s' status failed: %v", md.prev.DeviceName, err)
 	}
+	setReportError(err)
+}
+
+// LastReportError returns the error of the last report of device status, nil if it succeeded.
+func LastReportError() error {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	return reportErr
+}
+
+func setReportError(err error) {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	reportErr = err
 }
@@ -88,4 +88,5 @@ func TestReport(t *testing.T) {
 	// wait for ticker trigger
 	time.Sleep(time.Second * time.Duration(interval+1))
 	device.Stop()
+	require.Error(t, LastReportError())
 }
@@ -19,11 +19,14 @@ package api
 import (
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/service"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/runtime"
 )
 
-// DriverConfigs retrieves the driver specific configuration
+// DriverConfigs retrieves the driver specific configuration, which is updated once the Driver section changes
+// in the configuration provider.
 func DriverConfigs() map[string]string {
-	return service.RunningService().DriverConfigs()
+	return runtime.DriverConfigs()
 }
 
 // DeviceCommand retrieves the specific DeviceCommand instance from cache according to
@@ -21,7 +21,9 @@ import (
 
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/service"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 
+	"github.com/volcengine/vei-driver-sdk-go/internal/controller/common"
 	"github.com/volcengine/vei-driver-sdk-go/internal/runtime"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
 )
@@ -47,6 +49,17 @@ func SetDeviceOnline(deviceName string) {
 	runtime.StatusManager().SetDeviceOnline(deviceName)
 }
 
+// WriteErrorResponse writes the EdgeX error in the same format as the built-in controllers, which can be used by
+// the routes of driver.
+func WriteErrorResponse(w http.ResponseWriter, edgexErr errors.EdgeX) {
+	common.WriteErrorResponse(w, edgexErr)
+}
+
+// WriteResponse writes the data in json format with the status code.
+func WriteResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	common.WriteResponse(w, statusCode, data)
+}
+
 func ReportEvent(event *contracts.AsyncValues) error {
 	return runtime.Reporter().ReportEvent(event)
 }
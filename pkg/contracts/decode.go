@@ -0,0 +1,129 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+// The attributes of requests refer to the same map of the device profile cached by the device service until the
+// profile is updated, so the attributes decoded are cached for each resource of the profile and type of target, and
+// reused by all the requests to the resource rather than decoded on every request. The entry is replaced once the
+// attributes of the resource are updated, and purged by PurgeAttributes once the devices of the profile are updated.
+var attributeCache sync.Map
+
+type attributeKey struct {
+	profile  string
+	resource string
+	target   reflect.Type
+}
+
+type attributeEntry struct {
+	attributes map[string]interface{} // the attributes decoded, compared with the ones of the later requests
+	value      reflect.Value
+}
+
+// PurgeAttributes purges the attributes decoded for the resources of the profile.
+func PurgeAttributes(profile string) {
+	attributeCache.Range(func(key, _ interface{}) bool {
+		if key.(attributeKey).profile == profile {
+			attributeCache.Delete(key)
+		}
+		return true
+	})
+}
+
+// decodeAttributes decodes the attributes of the resource into the target by utils.Decode, the result is cached if
+// succeeded and the profile of the resource is known.
+func decodeAttributes(profile, resource string, attributes map[string]interface{}, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if profile == "" || len(attributes) == 0 || value.Kind() != reflect.Ptr || value.IsNil() {
+		return utils.Decode(attributes, target)
+	}
+	key := attributeKey{profile: profile, resource: resource, target: value.Type()}
+	if cached, ok := attributeCache.Load(key); ok {
+		entry := cached.(*attributeEntry)
+		if reflect.ValueOf(entry.attributes).Pointer() == reflect.ValueOf(attributes).Pointer() {
+			value.Elem().Set(deepCopy(entry.value))
+			return nil
+		}
+	}
+	if err := utils.Decode(attributes, target); err != nil {
+		return err
+	}
+	attributeCache.Store(key, &attributeEntry{attributes: attributes, value: deepCopy(value.Elem())})
+	return nil
+}
+
+// deepCopy returns a copy of the value whose slices, maps and pointers are copied as well, so that the value cached
+// is not modified through the ones returned to drivers. The unexported fields of structs are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+type testAttributes struct {
+	Address   uint16   `json:"address" validate:"required"`
+	ByteOrder string   `json:"byteOrder" default:"ABCD"`
+	Bits      []string `json:"bits"`
+}
+
+func cachedAttributes() int {
+	n := 0
+	attributeCache.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func readRequest(profile string, cr models.CommandRequest) ReadRequest {
+	req := NewReadRequest(cr)
+	SetProfile(req, profile)
+	return req
+}
+
+func TestDecodeAttributes(t *testing.T) {
+	attributes := map[string]interface{}{"address": "40001", "bits": "0,1"}
+	cr := models.CommandRequest{DeviceResourceName: "temperature", Attributes: attributes}
+	n := cachedAttributes()
+
+	var decoded testAttributes
+	require.NoError(t, readRequest("sensor", cr).DecodeAttributes(&decoded))
+	require.Equal(t, testAttributes{Address: 40001, ByteOrder: "ABCD", Bits: []string{"0", "1"}}, decoded)
+	require.Equal(t, n+1, cachedAttributes())
+
+	// The attributes of the same profile are decoded once.
+	var cached testAttributes
+	write := NewWriteRequest(cr, nil)
+	SetProfile(write, "sensor")
+	require.NoError(t, write.DecodeAttributes(&cached))
+	require.Equal(t, decoded, cached)
+	require.Equal(t, n+1, cachedAttributes())
+
+	// The attributes of the updated profile are decoded again and replace the entry.
+	cr.Attributes = map[string]interface{}{"address": 40002, "byteOrder": "DCBA"}
+	var updated testAttributes
+	require.NoError(t, readRequest("sensor", cr).DecodeAttributes(&updated))
+	require.Equal(t, testAttributes{Address: 40002, ByteOrder: "DCBA"}, updated)
+	require.Equal(t, n+1, cachedAttributes())
+
+	// The same resource of other profiles is cached separately.
+	require.NoError(t, readRequest("meter", cr).DecodeAttributes(&updated))
+	require.Equal(t, n+2, cachedAttributes())
+
+	// The attributes of the requests whose profile is unknown are not cached.
+	require.NoError(t, NewReadRequest(cr).DecodeAttributes(&updated))
+	require.Equal(t, n+2, cachedAttributes())
+
+	PurgeAttributes("sensor")
+	require.Equal(t, n+1, cachedAttributes())
+	PurgeAttributes("meter")
+	require.Equal(t, n, cachedAttributes())
+}
+
+func TestDecodeAttributesFailed(t *testing.T) {
+	cr := models.CommandRequest{DeviceResourceName: "temperature", Attributes: map[string]interface{}{"address": "x"}}
+	n := cachedAttributes()
+
+	var decoded testAttributes
+	err := readRequest("sensor", cr).DecodeAttributes(&decoded)
+	require.Error(t, err)
+	kind, ok := KindOf(err)
+	require.True(t, ok)
+	require.Equal(t, AttributeParseFailed, kind)
+	require.Contains(t, err.Error(), "temperature")
+	require.Equal(t, n, cachedAttributes())
+
+	cr.Attributes = nil
+	err = NewReadRequest(cr).DecodeAttributes(&decoded)
+	require.Error(t, err)
+	require.Equal(t, n, cachedAttributes())
+
+	err = NewReadRequest(cr).DecodeAttributes(decoded)
+	require.Error(t, err)
+}
+
+func TestDecodeAttributesCopied(t *testing.T) {
+	type nested struct {
+		Registers []uint16          `json:"registers"`
+		Labels    map[string]string `json:"labels"`
+		Scale     *float64          `json:"scale"`
+	}
+	cr := models.CommandRequest{DeviceResourceName: "registers", Attributes: map[string]interface{}{
+		"registers": []interface{}{1, 2},
+		"labels":    map[string]interface{}{"unit": "kPa"},
+		"scale":     0.1,
+	}}
+	defer PurgeAttributes("copied")
+
+	var decoded nested
+	require.NoError(t, readRequest("copied", cr).DecodeAttributes(&decoded))
+	expected := nested{Registers: []uint16{1, 2}, Labels: map[string]string{"unit": "kPa"}, Scale: decoded.Scale}
+	require.Equal(t, expected, decoded)
+
+	// the targets modified by drivers don't change the cached attributes
+	for i := 0; i < 2; i++ {
+		decoded.Registers[0] = 100
+		decoded.Registers = append(decoded.Registers[:1], 200)
+		decoded.Labels["unit"] = "Pa"
+		*decoded.Scale = 10
+
+		decoded = nested{}
+		require.NoError(t, readRequest("copied", cr).DecodeAttributes(&decoded))
+		require.Equal(t, []uint16{1, 2}, decoded.Registers)
+		require.Equal(t, map[string]string{"unit": "kPa"}, decoded.Labels)
+		require.Equal(t, 0.1, *decoded.Scale)
+	}
+}
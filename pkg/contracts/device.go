@@ -18,8 +18,11 @@ package contracts
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
 )
 
 // OperatingState is an indication of the operations of the device.
@@ -37,12 +40,26 @@ func (s OperatingState) String() string {
 	return string(s)
 }
 
+// AdminState is an indication of whether the device is locked by the operator, the locked device is excluded from
+// the status reporting and polling.
+type AdminState string
+
+const (
+	LOCKED   AdminState = "LOCKED"
+	UNLOCKED AdminState = "UNLOCKED"
+)
+
+func (s AdminState) String() string {
+	return string(s)
+}
+
 // Device contains the necessary information of a device.
 type Device struct {
 	Name           string                               `json:"name"`
 	Protocols      map[string]models.ProtocolProperties `json:"protocols"`
 	OperatingState OperatingState                       `json:"operating_state,omitempty"`
 	Message        string                               `json:"message,omitempty"`
+	AdminState     AdminState                           `json:"admin_state,omitempty"`
 }
 
 func WrapDevice(name string, protocols map[string]models.ProtocolProperties) *Device {
@@ -55,6 +72,26 @@ func (d *Device) GetProtocolByName(name string) (models.ProtocolProperties, bool
 	return protocol, exist
 }
 
+// DecodeProtocol decodes the properties of the protocol specified by name into the target which must be a pointer
+// to struct, the fields are named by the 'json' tags, and the absent ones are set to the 'default' tags. The strings
+// are converted to numbers, bools, durations and comma-separated lists, and the target is validated by the 'validate'
+// tags. ProtocolMissing or ProtocolParseFailed is returned on failure, which sets the device DOWN by UpdateStateByError.
+func (d *Device) DecodeProtocol(name string, target interface{}) error {
+	protocol, exist := d.GetProtocolByName(name)
+	if !exist {
+		return NewErrorWithReason(ProtocolMissing, fmt.Sprintf("protocol '%s' of device '%s' is missing", name, d.Name))
+	}
+	if err := utils.Decode(map[string]string(protocol), target); err != nil {
+		return NewErrorWithReason(ProtocolParseFailed, fmt.Sprintf("protocol '%s' of device '%s': %v", name, d.Name, err))
+	}
+	return nil
+}
+
+// IsLocked returns whether the device is locked by the operator.
+func (d *Device) IsLocked() bool {
+	return d.AdminState == LOCKED
+}
+
 // SetStateUp set the device state to UP.
 func (d *Device) SetStateUp() {
 	d.OperatingState = UP
@@ -19,6 +19,7 @@ package contracts
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
 	"github.com/stretchr/testify/require"
@@ -93,6 +94,56 @@ func TestDevice_SetOperatingState(t *testing.T) {
 	require.Equal(t, message, device.Message)
 }
 
+func TestDevice_DecodeProtocol(t *testing.T) {
+	type modbusTCP struct {
+		Host     string        `json:"host" validate:"required,hostname|ip"`
+		Port     uint16        `json:"port" default:"502"`
+		SlaveID  uint8         `json:"slaveId" validate:"min=1"`
+		Timeout  time.Duration `json:"timeout" default:"5s"`
+		KeepOpen bool          `json:"keepOpen"`
+		Backups  []string      `json:"backups" validate:"dive,ip"`
+	}
+	device := WrapDevice("device", map[string]models.ProtocolProperties{
+		"modbus-tcp": {"host": "192.168.1.10", "slaveId": "1", "timeout": "500", "keepOpen": "true", "backups": "192.168.1.11,192.168.1.12"},
+		"modbus-rtu": {"host": "192.168.1.10", "slaveId": "0"},
+	})
+
+	var protocol modbusTCP
+	require.NoError(t, device.DecodeProtocol("modbus-tcp", &protocol))
+	require.Equal(t, modbusTCP{
+		Host:     "192.168.1.10",
+		Port:     502,
+		SlaveID:  1,
+		Timeout:  500 * time.Millisecond,
+		KeepOpen: true,
+		Backups:  []string{"192.168.1.11", "192.168.1.12"},
+	}, protocol)
+
+	err := device.DecodeProtocol("opc-ua", &protocol)
+	kind, _ := KindOf(err)
+	require.Equal(t, ProtocolMissing, kind)
+
+	err = device.DecodeProtocol("modbus-rtu", &protocol)
+	kind, _ = KindOf(err)
+	require.Equal(t, ProtocolParseFailed, kind)
+
+	device.UpdateStateByError(err)
+	require.Equal(t, DOWN, device.OperatingState)
+	require.Equal(t, err.Error(), device.Message)
+}
+
+func TestDevice_IsLocked(t *testing.T) {
+	device := WrapDevice("device", nil)
+	require.False(t, device.IsLocked())
+
+	device.AdminState = UNLOCKED
+	require.False(t, device.IsLocked())
+	require.Equal(t, "UNLOCKED", device.AdminState.String())
+
+	device.AdminState = LOCKED
+	require.True(t, device.IsLocked())
+}
+
 func TestDevice_UpdateStateByError(t *testing.T) {
 	kind := ErrorKind("ParseFailed")
 	reason := "parse failed"
@@ -16,6 +16,14 @@
 
 package contracts
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	edgexerrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
 type ErrorKind string
 
 const (
@@ -39,24 +47,249 @@ const (
 	ReadTimeout             ErrorKind = "数据读取超时"
 	WriteError              ErrorKind = "数据写入错误"
 	WriteTimeout            ErrorKind = "数据写入超时"
+	CallTimeout             ErrorKind = "服务调用超时"
+	DriverPanic             ErrorKind = "驱动运行异常"
 )
 
 type Error struct {
 	kind   ErrorKind
 	reason string
+	err    error
 }
 
+// NewError wraps err as the cause of an Error of the kind, whose reason is the message of err.
 func NewError(kind ErrorKind, err error) *Error {
 	if err == nil {
 		return NewErrorWithReason(kind, "Unknown")
 	}
-	return NewErrorWithReason(kind, err.Error())
+	return &Error{kind: kind, reason: err.Error(), err: err}
 }
 
 func NewErrorWithReason(kind ErrorKind, reason string) *Error {
 	return &Error{kind: kind, reason: reason}
 }
 
+// Error returns the message of the kind in the current language followed by the reason.
 func (e *Error) Error() string {
-	return string(e.kind) + ": " + e.reason
+	return e.kind.Message(CurrentLanguage()) + ": " + e.reason
+}
+
+// Unwrap returns the cause of the error, nil if it's created with a reason only.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the target is an Error of the same kind, the reason of the target is also compared unless empty.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.kind == e.kind && (t.reason == "" || t.reason == e.reason)
+}
+
+// Kind returns the kind of the error.
+func (e *Error) Kind() ErrorKind {
+	return e.kind
+}
+
+// Reason returns the reason of the error.
+func (e *Error) Reason() string {
+	return e.reason
+}
+
+// Code returns the stable code of the kind of the error.
+func (e *Error) Code() string {
+	return e.kind.Code()
+}
+
+// Retryable indicates whether the error is transient according to its kind.
+func (e *Error) Retryable() bool {
+	return e.kind.Retryable()
+}
+
+// EdgeXKind returns the kind of EdgeX error which the error maps to.
+func (e *Error) EdgeXKind() edgexerrors.ErrKind {
+	return e.kind.EdgeXKind()
+}
+
+// KindOf returns the kind of the first Error found in the chain of err.
+func KindOf(err error) (ErrorKind, bool) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return "", false
+	}
+	return e.kind, true
+}
+
+// EdgeXKindOf returns the kind of EdgeX error which err maps to, KindServerError if it's not classified.
+func EdgeXKindOf(err error) edgexerrors.ErrKind {
+	var e interface{ EdgeXKind() edgexerrors.ErrKind }
+	if errors.As(err, &e) {
+		return e.EdgeXKind()
+	}
+	return edgexerrors.KindServerError
+}
+
+// ToEdgeX converts err into an EdgeX error whose status code is decided by EdgeXKindOf, it's returned as is if
+// it's already an EdgeX error. The status code only reaches the callers of the routes served by the SDK, since the
+// device service wraps the errors of drivers as KindServerError, so core-command always responds 500. The codes of
+// err are therefore put in the message as well, which are the codes of the kinds such as "[ReadTimeout,WriteError]
+// -> ...", and kept in the response body.
+func ToEdgeX(err error) edgexerrors.EdgeX {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(edgexerrors.EdgeX); ok {
+		return e
+	}
+	var message string
+	if codes := codesOf(err); len(codes) > 0 {
+		message = "[" + strings.Join(codes, ",") + "]"
+	}
+	return edgexerrors.NewCommonEdgeX(EdgeXKindOf(err), message, err)
+}
+
+// codesOf returns the distinct codes of the failed requests if err is a BatchError, or the code of the Error found
+// in the chain of err otherwise.
+func codesOf(err error) []string {
+	var batch *BatchError
+	if errors.As(err, &batch) {
+		codes, seen := make([]string, 0, len(batch.Errors)), make(map[string]bool)
+		for _, re := range batch.Errors {
+			if re.Code != "" && !seen[re.Code] {
+				codes, seen[re.Code] = append(codes, re.Code), true
+			}
+		}
+		return codes
+	}
+	if kind, ok := KindOf(err); ok {
+		return []string{kind.Code()}
+	}
+	return nil
+}
+
+// RequestError is the error of the request to a resource.
+type RequestError struct {
+	Resource string    `json:"resource"`
+	Kind     ErrorKind `json:"kind,omitempty"`
+	Code     string    `json:"code,omitempty"`
+	Reason   string    `json:"reason"`
+	err      error
+}
+
+// NewRequestError wraps the error of the request to the resource, the kind is empty if err is not an Error.
+func NewRequestError(resource string, err error) *RequestError {
+	re := &RequestError{Resource: resource, Reason: "Unknown", err: err}
+	if err == nil {
+		return re
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		re.Kind, re.Code, re.Reason = e.kind, e.Code(), e.reason
+	} else {
+		re.Reason = err.Error()
+	}
+	return re
+}
+
+func (e *RequestError) Error() string {
+	if e.Kind == "" {
+		return e.Resource + ": " + e.Reason
+	}
+	return e.Resource + ": " + e.Kind.Message(CurrentLanguage()) + ": " + e.Reason
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.err
+}
+
+// EdgeXKind returns the kind of EdgeX error which the request error maps to, KindServerError if it's not classified.
+func (e *RequestError) EdgeXKind() edgexerrors.ErrKind {
+	return e.Kind.EdgeXKind()
+}
+
+// BatchError collects the outcomes of the requests handled in a batch, it's an error if any request failed.
+type BatchError struct {
+	Errors    []*RequestError `json:"errors"`
+	Succeeded []string        `json:"succeeded,omitempty"`
+}
+
+// Add records the outcome of the request to the resource, err is nil if it succeeded.
+func (e *BatchError) Add(resource string, err error) {
+	if err == nil {
+		e.Succeeded = append(e.Succeeded, resource)
+		return
+	}
+	e.Errors = append(e.Errors, NewRequestError(resource, err))
+}
+
+// Failed indicates whether any request failed.
+func (e *BatchError) Failed() bool {
+	return len(e.Errors) > 0
+}
+
+// Err returns the BatchError if any request failed, nil otherwise.
+func (e *BatchError) Err() error {
+	if !e.Failed() {
+		return nil
+	}
+	return e
+}
+
+func (e *BatchError) Error() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d of %d requests failed: ", len(e.Errors), len(e.Errors)+len(e.Succeeded)))
+	for i, re := range e.Errors {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString("[" + re.Error() + "]")
+	}
+	if len(e.Succeeded) > 0 {
+		b.WriteString(", succeeded: " + strings.Join(e.Succeeded, ", "))
+	}
+	return b.String()
+}
+
+// EdgeXKind returns the kind of EdgeX error shared by the failed requests, KindServerError if they differ.
+func (e *BatchError) EdgeXKind() edgexerrors.ErrKind {
+	kind := edgexerrors.KindServerError
+	for i, re := range e.Errors {
+		if i == 0 {
+			kind = re.EdgeXKind()
+		} else if re.EdgeXKind() != kind {
+			return edgexerrors.KindServerError
+		}
+	}
+	return kind
+}
+
+// Is reports whether any failed request matches the target, since errors.Is walks Unwrap() []error from Go 1.20 only.
+func (e *BatchError) Is(target error) bool {
+	for _, re := range e.Errors {
+		if errors.Is(re, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first failed request which matches the target, since errors.As walks Unwrap() []error from Go 1.20 only.
+func (e *BatchError) As(target interface{}) bool {
+	for _, re := range e.Errors {
+		if errors.As(re, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the errors of the failed requests.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, re := range e.Errors {
+		errs = append(errs, re)
+	}
+	return errs
 }
@@ -18,8 +18,12 @@ package contracts
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
+	edgexerrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -28,7 +32,138 @@ func TestError(t *testing.T) {
 
 	err1 := NewError(ErrorKind("ParseFailed"), raw)
 	err2 := NewErrorWithReason(ErrorKind("ParseFailed"), raw.Error())
-	require.Equal(t, err1, err2)
+	require.Equal(t, err1.Error(), err2.Error())
+	require.Equal(t, raw, errors.Unwrap(err1))
+	require.Nil(t, errors.Unwrap(err2))
+	require.ErrorIs(t, fmt.Errorf("wrapped: %w", err1), raw)
+
+	// errors of the same kind match, and the reason is compared unless empty
+	require.ErrorIs(t, err1, NewErrorWithReason(ErrorKind("ParseFailed"), ""))
+	require.ErrorIs(t, err1, err2)
+	require.NotErrorIs(t, err1, NewErrorWithReason(ErrorKind("ParseFailed"), "other"))
+	require.NotErrorIs(t, err1, NewErrorWithReason(ReadError, ""))
 
 	t.Log(err1.Error())
 }
+
+func TestErrorKind(t *testing.T) {
+	err := NewErrorWithReason(ReadTimeout, "no response")
+	require.Equal(t, "ReadTimeout", err.Code())
+	require.True(t, err.Retryable())
+	require.Equal(t, edgexerrors.KindCommunicationError, err.EdgeXKind())
+
+	require.False(t, ParameterParseFailed.Retryable())
+	require.Equal(t, edgexerrors.KindContractInvalid, ParameterParseFailed.EdgeXKind())
+	require.Equal(t, edgexerrors.KindEntityDoesNotExist, DeviceNotFound.EdgeXKind())
+
+	// the customized kinds are kept as they are
+	custom := ErrorKind("ParseFailed")
+	require.Equal(t, "ParseFailed", custom.Code())
+	require.Equal(t, "ParseFailed", custom.Message(English))
+	require.Equal(t, edgexerrors.KindServerError, custom.EdgeXKind())
+
+	// each kind has a unique code and an English message
+	codes := map[string]bool{}
+	for kind, info := range kindInfos {
+		require.NotEmpty(t, info.english, kind)
+		require.False(t, codes[kind.Code()], kind)
+		codes[kind.Code()] = true
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	defer func() { _ = SetLanguage("") }()
+	err := NewErrorWithReason(ReadTimeout, "no response")
+	require.Equal(t, Chinese, CurrentLanguage())
+	require.Equal(t, "数据读取超时: no response", err.Error())
+
+	require.NoError(t, SetLanguage("en-US"))
+	require.Equal(t, English, CurrentLanguage())
+	require.Equal(t, "read timeout: no response", err.Error())
+	require.Equal(t, "humidity: read timeout: no response", NewRequestError("humidity", err).Error())
+
+	require.Error(t, SetLanguage("fr"))
+	require.Equal(t, English, CurrentLanguage())
+
+	require.NoError(t, SetLanguage("zh_CN"))
+	require.Equal(t, "数据读取超时: no response", err.Error())
+}
+
+func TestToEdgeX(t *testing.T) {
+	require.Nil(t, ToEdgeX(nil))
+
+	err := ToEdgeX(fmt.Errorf("wrapped: %w", NewErrorWithReason(DeviceNotFound, "device-1")))
+	require.Equal(t, http.StatusNotFound, err.Code())
+	require.Equal(t, edgexerrors.KindEntityDoesNotExist, edgexerrors.Kind(err))
+	require.Equal(t, "["+DeviceNotFound.Code()+"] -> wrapped: 设备未找到: device-1", err.Error())
+	kind, ok := KindOf(err)
+	require.True(t, ok)
+	require.Equal(t, DeviceNotFound, kind)
+
+	require.Equal(t, http.StatusInternalServerError, ToEdgeX(errors.New("raw")).Code())
+	require.Equal(t, "raw", ToEdgeX(errors.New("raw")).Error())
+
+	edgexErr := edgexerrors.NewCommonEdgeX(edgexerrors.KindServiceUnavailable, "busy", nil)
+	require.Equal(t, edgexErr, ToEdgeX(edgexErr))
+
+	// the batch maps to the kind shared by the failed requests
+	batch := &BatchError{}
+	batch.Add("temperature", NewErrorWithReason(ParameterParseFailed, "not a number"))
+	batch.Add("humidity", NewErrorWithReason(ParameterParseFailed, "out of range"))
+	require.Equal(t, http.StatusBadRequest, ToEdgeX(batch).Code())
+	batch.Add("pressure", NewErrorWithReason(WriteTimeout, "no response"))
+	require.Equal(t, http.StatusInternalServerError, ToEdgeX(batch).Code())
+
+	// the codes are kept in the message, which still reaches core-command after the device service wraps the
+	// error as KindServerError
+	wrapped := edgexerrors.NewCommonEdgeX(edgexerrors.KindServerError, "error writing DeviceResource", ToEdgeX(batch))
+	require.Equal(t, http.StatusInternalServerError, wrapped.Code())
+	require.True(t, strings.HasPrefix(wrapped.Error(),
+		"error writing DeviceResource -> ["+ParameterParseFailed.Code()+","+WriteTimeout.Code()+"] -> "))
+}
+
+func TestKindOf(t *testing.T) {
+	err := NewErrorWithReason(ReadTimeout, "no response")
+	require.Equal(t, ReadTimeout, err.Kind())
+	require.Equal(t, "no response", err.Reason())
+
+	kind, ok := KindOf(fmt.Errorf("wrapped: %w", err))
+	require.True(t, ok)
+	require.Equal(t, ReadTimeout, kind)
+
+	_, ok = KindOf(errors.New("raw"))
+	require.False(t, ok)
+}
+
+func TestBatchError(t *testing.T) {
+	batch := &BatchError{}
+	batch.Add("temperature", nil)
+	require.False(t, batch.Failed())
+	require.NoError(t, batch.Err())
+
+	batch.Add("humidity", NewErrorWithReason(ReadTimeout, "no response"))
+	batch.Add("pressure", errors.New("raw"))
+	require.True(t, batch.Failed())
+	require.Equal(t, "2 of 3 requests failed: [humidity: 数据读取超时: no response]; [pressure: raw], succeeded: temperature",
+		batch.Err().Error())
+
+	require.Equal(t, ReadTimeout, batch.Errors[0].Kind)
+	require.Equal(t, "ReadTimeout", batch.Errors[0].Code)
+	require.Equal(t, ErrorKind(""), batch.Errors[1].Kind)
+	require.Equal(t, "raw", batch.Errors[1].Reason)
+
+	var err *Error
+	require.True(t, errors.As(batch.Errors[0], &err))
+	var be *BatchError
+	require.True(t, errors.As(fmt.Errorf("wrapped: %w", batch), &be))
+	require.Len(t, be.Errors, 2)
+
+	// the failed requests are matched without relying on Unwrap() []error
+	require.True(t, batch.As(&err))
+	require.Equal(t, ReadTimeout, err.Kind())
+	require.True(t, batch.Is(NewErrorWithReason(ReadTimeout, "")))
+	require.False(t, batch.Is(NewErrorWithReason(ReadError, "")))
+	kind, ok := KindOf(batch)
+	require.True(t, ok)
+	require.Equal(t, ReadTimeout, kind)
+}
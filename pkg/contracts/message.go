@@ -0,0 +1,128 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	edgexerrors "github.com/edgexfoundry/go-mod-core-contracts/v2/errors"
+)
+
+// Language selects the catalogue of the messages of errors, which are shown as the reasons of the device status.
+type Language string
+
+const (
+	Chinese Language = "zh"
+	English Language = "en"
+)
+
+var language atomic.Value
+
+func init() {
+	language.Store(Chinese)
+}
+
+// SetLanguage switches the language of the messages of errors, such as 'zh', 'en' or 'en-US', Chinese is used if empty.
+func SetLanguage(lang string) error {
+	tag := strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	switch Language(tag) {
+	case "", Chinese:
+		language.Store(Chinese)
+	case English:
+		language.Store(English)
+	default:
+		return fmt.Errorf("unsupported language '%s'", lang)
+	}
+	return nil
+}
+
+// CurrentLanguage returns the language of the messages of errors in use.
+func CurrentLanguage() Language {
+	return language.Load().(Language)
+}
+
+// kindInfo describes an ErrorKind, its Chinese message is the kind itself.
+type kindInfo struct {
+	code    string
+	english string
+	edgex   edgexerrors.ErrKind
+}
+
+var kindInfos = map[ErrorKind]kindInfo{
+	DeviceNotFound:          {"DeviceNotFound", "device not found", edgexerrors.KindEntityDoesNotExist},
+	DeviceLoadFailed:        {"DeviceLoadFailed", "device load failed", edgexerrors.KindServerError},
+	ProtocolMissing:         {"ProtocolMissing", "protocol missing", edgexerrors.KindContractInvalid},
+	ProtocolParseFailed:     {"ProtocolParseFailed", "protocol parse failed", edgexerrors.KindContractInvalid},
+	ProtocolUnsupported:     {"ProtocolUnsupported", "protocol unsupported", edgexerrors.KindNotImplemented},
+	AuthFailed:              {"AuthFailed", "authentication failed", edgexerrors.KindCommunicationError},
+	LoginFailed:             {"LoginFailed", "login failed", edgexerrors.KindCommunicationError},
+	WrongPassword:           {"WrongPassword", "wrong username or password", edgexerrors.KindCommunicationError},
+	ConnectionFailed:        {"ConnectionFailed", "connection failed", edgexerrors.KindCommunicationError},
+	ConnectionTimeout:       {"ConnectionTimeout", "connection timeout", edgexerrors.KindCommunicationError},
+	NetworkUnreachable:      {"NetworkUnreachable", "network unreachable", edgexerrors.KindCommunicationError},
+	ResourceNotFound:        {"ResourceNotFound", "resource not found", edgexerrors.KindEntityDoesNotExist},
+	ResourceTypeUnsupported: {"ResourceTypeUnsupported", "resource type unsupported", edgexerrors.KindNotImplemented},
+	AttributeParseFailed:    {"AttributeParseFailed", "attribute parse failed", edgexerrors.KindContractInvalid},
+	ParameterParseFailed:    {"ParameterParseFailed", "parameter parse failed", edgexerrors.KindContractInvalid},
+	DataParseError:          {"DataParseError", "data parse error", edgexerrors.KindServerError},
+	ReadError:               {"ReadError", "read error", edgexerrors.KindCommunicationError},
+	ReadTimeout:             {"ReadTimeout", "read timeout", edgexerrors.KindCommunicationError},
+	WriteError:              {"WriteError", "write error", edgexerrors.KindCommunicationError},
+	WriteTimeout:            {"WriteTimeout", "write timeout", edgexerrors.KindCommunicationError},
+	CallTimeout:             {"CallTimeout", "call timeout", edgexerrors.KindCommunicationError},
+	DriverPanic:             {"DriverPanic", "driver panic", edgexerrors.KindServerError},
+}
+
+// Code returns the stable code of the kind, which doesn't change with the language, the kind itself is
+// returned if it's customized.
+func (k ErrorKind) Code() string {
+	if info, ok := kindInfos[k]; ok {
+		return info.code
+	}
+	return string(k)
+}
+
+// Message returns the message of the kind in the language, the kind itself is returned if it's customized.
+func (k ErrorKind) Message(lang Language) string {
+	if info, ok := kindInfos[k]; ok && lang == English {
+		return info.english
+	}
+	return string(k)
+}
+
+// EdgeXKind returns the kind of EdgeX error which the kind maps to, KindServerError if it's customized.
+func (k ErrorKind) EdgeXKind() edgexerrors.ErrKind {
+	if info, ok := kindInfos[k]; ok {
+		return info.edgex
+	}
+	return edgexerrors.KindServerError
+}
+
+// Retryable indicates whether the errors of the kind are transient, that is one of DefaultRetryableKinds.
+func (k ErrorKind) Retryable() bool {
+	for _, kind := range DefaultRetryableKinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
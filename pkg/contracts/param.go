@@ -0,0 +1,236 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/spf13/cast"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
+)
+
+// ParamAs returns the parameter of the write request converted into T, see ParamInto for the conversions.
+func ParamAs[T any](req WriteRequest) (T, error) {
+	var value T
+	err := req.ParamInto(&value)
+	return value, err
+}
+
+// paramInto converts the parameter into the target, the error is wrapped as ParameterParseFailed.
+func paramInto(param *sdkmodels.CommandValue, target interface{}) error {
+	if param == nil {
+		return NewErrorWithReason(ParameterParseFailed, "missing parameter")
+	}
+	if err := convertParam(param, target); err != nil {
+		return NewErrorWithReason(ParameterParseFailed, fmt.Sprintf("resource '%s': %v", param.DeviceResourceName, err))
+	}
+	return nil
+}
+
+func convertParam(param *sdkmodels.CommandValue, target interface{}) error {
+	dst := reflect.ValueOf(target)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return fmt.Errorf("target must be a non-nil pointer, got %T", target)
+	}
+	dst = dst.Elem()
+	value, err := paramValue(param)
+	if err != nil {
+		return err
+	}
+	src := reflect.ValueOf(value)
+	if src.IsValid() && src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	switch ValueType(param.Type) {
+	case Object:
+		// The object is decoded into the structs, maps or slices by its JSON representation.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, target)
+	case Binary:
+		return fmt.Errorf("cannot convert %s parameter into %s", param.Type, dst.Type())
+	}
+	if src.Kind() != reflect.Slice {
+		return assignScalar(dst, value)
+	}
+	if dst.Kind() != reflect.Slice {
+		return fmt.Errorf("cannot convert %s parameter into %s", param.Type, dst.Type())
+	}
+	elems := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+	for i := 0; i < src.Len(); i++ {
+		if err = assignScalar(elems.Index(i), src.Index(i).Interface()); err != nil {
+			return fmt.Errorf("element %d: %v", i, err)
+		}
+	}
+	dst.Set(elems)
+	return nil
+}
+
+// paramValue returns the value of the parameter in the type corresponding to its ValueType.
+func paramValue(param *sdkmodels.CommandValue) (interface{}, error) {
+	switch ValueType(param.Type) {
+	case Bool:
+		return param.BoolValue()
+	case String:
+		return param.StringValue()
+	case Uint8:
+		return param.Uint8Value()
+	case Uint16:
+		return param.Uint16Value()
+	case Uint32:
+		return param.Uint32Value()
+	case Uint64:
+		return param.Uint64Value()
+	case Int8:
+		return param.Int8Value()
+	case Int16:
+		return param.Int16Value()
+	case Int32:
+		return param.Int32Value()
+	case Int64:
+		return param.Int64Value()
+	case Float32:
+		return param.Float32Value()
+	case Float64:
+		return param.Float64Value()
+	case Binary:
+		return param.BinaryValue()
+	case BoolArray:
+		return param.BoolArrayValue()
+	case StringArray:
+		return param.StringArrayValue()
+	case Uint8Array:
+		return param.Uint8ArrayValue()
+	case Uint16Array:
+		return param.Uint16ArrayValue()
+	case Uint32Array:
+		return param.Uint32ArrayValue()
+	case Uint64Array:
+		return param.Uint64ArrayValue()
+	case Int8Array:
+		return param.Int8ArrayValue()
+	case Int16Array:
+		return param.Int16ArrayValue()
+	case Int32Array:
+		return param.Int32ArrayValue()
+	case Int64Array:
+		return param.Int64ArrayValue()
+	case Float32Array:
+		return param.Float32ArrayValue()
+	case Float64Array:
+		return param.Float64ArrayValue()
+	case Object:
+		return param.ObjectValue()
+	default:
+		return nil, fmt.Errorf("unsupported value type: %s", param.Type)
+	}
+}
+
+// kindValueTypes are the value types whose ranges are checked when converting into the kinds.
+var kindValueTypes = map[reflect.Kind]ValueType{
+	reflect.Int:     utils.Ternary(strconv.IntSize == 32, Int32, Int64),
+	reflect.Int8:    Int8,
+	reflect.Int16:   Int16,
+	reflect.Int32:   Int32,
+	reflect.Int64:   Int64,
+	reflect.Uint:    utils.Ternary(strconv.IntSize == 32, Uint32, Uint64),
+	reflect.Uint8:   Uint8,
+	reflect.Uint16:  Uint16,
+	reflect.Uint32:  Uint32,
+	reflect.Uint64:  Uint64,
+	reflect.Float32: Float32,
+	reflect.Float64: Float64,
+}
+
+// assignScalar converts the scalar value into the kind of dst, the numbers are checked against the range of dst.
+func assignScalar(dst reflect.Value, value interface{}) error {
+	if valueType, ok := kindValueTypes[dst.Kind()]; ok && !utils.CheckValueRange(string(valueType), value) {
+		return fmt.Errorf("%v is out of the range of %s", value, dst.Type())
+	}
+	switch dst.Kind() {
+	case reflect.Bool:
+		v, err := cast.ToBoolE(value)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(v)
+	case reflect.String:
+		v, err := cast.ToStringE(value)
+		if err != nil {
+			return err
+		}
+		dst.SetString(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if err := checkInteger(value); err != nil {
+			return err
+		}
+		v, err := cast.ToInt64E(value)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if err := checkInteger(value); err != nil {
+			return err
+		}
+		v, err := cast.ToUint64E(value)
+		if err != nil {
+			return err
+		}
+		dst.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := cast.ToFloat64E(value)
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(v)
+	case reflect.Interface:
+		if dst.NumMethod() > 0 {
+			return fmt.Errorf("cannot convert %T into %s", value, dst.Type())
+		}
+		dst.Set(reflect.ValueOf(value))
+	default:
+		return fmt.Errorf("cannot convert %T into %s", value, dst.Type())
+	}
+	return nil
+}
+
+// checkInteger returns an error if the value is a float with the fractional part, which is not truncated silently.
+func checkInteger(value interface{}) error {
+	var f float64
+	switch v := value.(type) {
+	case float32:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		return nil
+	}
+	if f != math.Trunc(f) {
+		return fmt.Errorf("%v is not an integer", value)
+	}
+	return nil
+}
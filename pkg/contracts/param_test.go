@@ -0,0 +1,135 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"math"
+	"testing"
+
+	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func mockWriteRequest(t *testing.T, valueType ValueType, value interface{}) WriteRequest {
+	param, err := sdkmodels.NewCommandValue("resource", string(valueType), value)
+	require.NoError(t, err)
+	return NewWriteRequest(MockCommandRequest("resource", string(valueType)), param)
+}
+
+func paramOf[T any](t *testing.T, valueType ValueType, value interface{}) T {
+	v, err := ParamAs[T](mockWriteRequest(t, valueType, value))
+	require.NoError(t, err)
+	return v
+}
+
+func TestParamAs(t *testing.T) {
+	require.Equal(t, true, paramOf[bool](t, Bool, true))
+	require.Equal(t, "text", paramOf[string](t, String, "text"))
+	require.Equal(t, uint8(8), paramOf[uint8](t, Uint8, uint8(8)))
+	require.Equal(t, uint16(16), paramOf[uint16](t, Uint16, uint16(16)))
+	require.Equal(t, uint32(32), paramOf[uint32](t, Uint32, uint32(32)))
+	require.Equal(t, uint64(64), paramOf[uint64](t, Uint64, uint64(64)))
+	require.Equal(t, int8(-8), paramOf[int8](t, Int8, int8(-8)))
+	require.Equal(t, int16(-16), paramOf[int16](t, Int16, int16(-16)))
+	require.Equal(t, int32(-32), paramOf[int32](t, Int32, int32(-32)))
+	require.Equal(t, int64(-64), paramOf[int64](t, Int64, int64(-64)))
+	require.Equal(t, float32(3.2), paramOf[float32](t, Float32, float32(3.2)))
+	require.Equal(t, 6.4, paramOf[float64](t, Float64, 6.4))
+	require.Equal(t, []byte{0x01, 0x02}, paramOf[[]byte](t, Binary, []byte{0x01, 0x02}))
+	require.Equal(t, []bool{true, false}, paramOf[[]bool](t, BoolArray, []bool{true, false}))
+	require.Equal(t, []string{"a", "b"}, paramOf[[]string](t, StringArray, []string{"a", "b"}))
+	require.Equal(t, []uint8{1, 2}, paramOf[[]uint8](t, Uint8Array, []uint8{1, 2}))
+	require.Equal(t, []uint16{1, 2}, paramOf[[]uint16](t, Uint16Array, []uint16{1, 2}))
+	require.Equal(t, []uint32{1, 2}, paramOf[[]uint32](t, Uint32Array, []uint32{1, 2}))
+	require.Equal(t, []uint64{1, 2}, paramOf[[]uint64](t, Uint64Array, []uint64{1, 2}))
+	require.Equal(t, []int8{-1, 2}, paramOf[[]int8](t, Int8Array, []int8{-1, 2}))
+	require.Equal(t, []int16{-1, 2}, paramOf[[]int16](t, Int16Array, []int16{-1, 2}))
+	require.Equal(t, []int32{-1, 2}, paramOf[[]int32](t, Int32Array, []int32{-1, 2}))
+	require.Equal(t, []int64{-1, 2}, paramOf[[]int64](t, Int64Array, []int64{-1, 2}))
+	require.Equal(t, []float32{1.5, 2}, paramOf[[]float32](t, Float32Array, []float32{1.5, 2}))
+	require.Equal(t, []float64{1.5, 2}, paramOf[[]float64](t, Float64Array, []float64{1.5, 2}))
+
+	type setpoint struct {
+		Value float64 `json:"value"`
+		Unit  string  `json:"unit"`
+	}
+	object := map[string]interface{}{"value": 21.5, "unit": "C"}
+	require.Equal(t, setpoint{Value: 21.5, Unit: "C"}, paramOf[setpoint](t, Object, object))
+	require.Equal(t, object, paramOf[map[string]interface{}](t, Object, object))
+	require.Equal(t, interface{}(object), paramOf[interface{}](t, Object, object))
+}
+
+func TestParamAsWithConversion(t *testing.T) {
+	require.Equal(t, 16, paramOf[int](t, Uint16, uint16(16)))
+	require.Equal(t, uint8(200), paramOf[uint8](t, Int32, int32(200)))
+	require.Equal(t, 2.0, paramOf[float64](t, Int8, int8(2)))
+	require.Equal(t, int64(3), paramOf[int64](t, Float64, 3.0))
+	require.Equal(t, "12", paramOf[string](t, Int32, int32(12)))
+	require.Equal(t, 42, paramOf[int](t, String, "42"))
+	require.Equal(t, []int{1, 2}, paramOf[[]int](t, Uint8Array, []uint8{1, 2}))
+	require.Equal(t, []float64{1, -2}, paramOf[[]float64](t, Int16Array, []int16{1, -2}))
+}
+
+func TestParamAsFailed(t *testing.T) {
+	assertFailed := func(err error) {
+		require.Error(t, err)
+		kind, _ := KindOf(err)
+		require.Equal(t, ParameterParseFailed, kind)
+	}
+
+	_, err := ParamAs[uint8](mockWriteRequest(t, Int32, int32(256)))
+	assertFailed(err)
+	_, err = ParamAs[uint32](mockWriteRequest(t, Int32, int32(-1)))
+	assertFailed(err)
+	_, err = ParamAs[int](mockWriteRequest(t, Float64, 1.5))
+	assertFailed(err)
+	_, err = ParamAs[float32](mockWriteRequest(t, Float64, 1e40))
+	assertFailed(err)
+	_, err = ParamAs[[]int8](mockWriteRequest(t, Int16Array, []int16{1, 128}))
+	assertFailed(err)
+	// the numbers out of the range are not wrapped around across the signs or from floats
+	_, err = ParamAs[int32](mockWriteRequest(t, Uint64, uint64(math.MaxUint64)))
+	assertFailed(err)
+	_, err = ParamAs[int64](mockWriteRequest(t, Uint64, uint64(math.MaxInt64+1)))
+	assertFailed(err)
+	_, err = ParamAs[int64](mockWriteRequest(t, Float64, 1e19))
+	assertFailed(err)
+	_, err = ParamAs[int64](mockWriteRequest(t, Float64, -1e19))
+	assertFailed(err)
+	_, err = ParamAs[uint64](mockWriteRequest(t, Float64, 2e19))
+	assertFailed(err)
+	_, err = ParamAs[uint8](mockWriteRequest(t, Float64, -1.0))
+	assertFailed(err)
+	_, err = ParamAs[[]int16](mockWriteRequest(t, Uint64Array, []uint64{1, math.MaxUint64}))
+	assertFailed(err)
+	_, err = ParamAs[int](mockWriteRequest(t, Int16Array, []int16{1}))
+	assertFailed(err)
+	_, err = ParamAs[string](mockWriteRequest(t, Binary, []byte{0x01}))
+	assertFailed(err)
+	_, err = ParamAs[struct{ Value int }](mockWriteRequest(t, Int32, int32(1)))
+	assertFailed(err)
+	_, err = ParamAs[struct {
+		Value int `json:"value"`
+	}](mockWriteRequest(t, Object, map[string]interface{}{"value": "text"}))
+	assertFailed(err)
+
+	req := NewWriteRequest(MockCommandRequest("resource", string(Int32)), nil)
+	_, err = ParamAs[int32](req)
+	assertFailed(err)
+	var value int32
+	assertFailed(mockWriteRequest(t, Int32, int32(1)).ParamInto(value))
+}
@@ -17,6 +17,8 @@
 package contracts
 
 import (
+	"fmt"
+
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 
 	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
@@ -33,6 +35,11 @@ type BaseRequest interface {
 	ValueType() ValueType
 	// Attributes returns the attributes defined for the thingmodel resource.
 	Attributes() map[string]interface{}
+	// DecodeAttributes decodes the attributes into the target which must be a pointer to struct, the fields are
+	// named by the 'json' tags, and the absent ones are set to the 'default' tags. The target is validated by the
+	// 'validate' tags, and AttributeParseFailed is returned on failure. The result is cached for the resource, and
+	// each target gets its own copy, whose slices and maps can be modified freely.
+	DecodeAttributes(target interface{}) error
 	// SetResult set the handle result of the request.
 	SetResult(result Result)
 	// Result get the handle result of the request.
@@ -55,6 +62,11 @@ type WriteRequest interface {
 	BaseRequest
 	// Param returns the input parameter of the write request.
 	Param() *models.CommandValue
+	// ParamInto converts the parameter into the target which must be a non-nil pointer. The parameter is assigned
+	// directly if the types match, the numbers are converted into other numeric kinds if they are in the range, the
+	// arrays are converted element by element, and the object is decoded by its JSON representation. The error is
+	// ParameterParseFailed if the parameter is missing or cannot be converted.
+	ParamInto(target interface{}) error
 }
 
 type CallRequest interface {
@@ -63,6 +75,10 @@ type CallRequest interface {
 	Payload() []byte
 }
 
+type EventRequest interface {
+	BaseRequest
+}
+
 func NewReadRequest(req models.CommandRequest) ReadRequest {
 	r := newRequest(req)
 	return r
@@ -84,6 +100,11 @@ func NewCallRequest(req models.CommandRequest) (CallRequest, error) {
 	return r, nil
 }
 
+func NewEventRequest(req models.CommandRequest) EventRequest {
+	r := newRequest(req)
+	return r
+}
+
 func newRequest(req models.CommandRequest) *request {
 	module, resource := SplitResourceName(req.DeviceResourceName)
 	return &request{
@@ -101,6 +122,7 @@ type request struct {
 	resource   string
 	valueType  ValueType
 	attributes map[string]interface{}
+	profile    string
 	result     Result
 	error      error
 	skipped    bool
@@ -128,6 +150,13 @@ func (r *request) Attributes() map[string]interface{} {
 	return r.attributes
 }
 
+func (r *request) DecodeAttributes(target interface{}) error {
+	if err := decodeAttributes(r.profile, r.native.DeviceResourceName, r.attributes, target); err != nil {
+		return NewErrorWithReason(AttributeParseFailed, fmt.Sprintf("resource '%s': %v", r.native.DeviceResourceName, err))
+	}
+	return nil
+}
+
 func (r *request) SetResult(result Result) {
 	r.result = result
 }
@@ -156,6 +185,25 @@ func (r *request) Param() *models.CommandValue {
 	return r.param
 }
 
+func (r *request) ParamInto(target interface{}) error {
+	return paramInto(r.param, target)
+}
+
 func (r *request) Payload() []byte {
 	return r.payload
 }
+
+// SetProfile sets the name of the profile where the resource of the request is defined, so that the attributes
+// decoded by DecodeAttributes are cached for the resource of the profile.
+func SetProfile(req BaseRequest, profile string) {
+	if r, ok := req.(*request); ok {
+		r.profile = profile
+	}
+}
+
+// Reset clears the result, error and skip flag of the request so that it can be handled again.
+func Reset(req BaseRequest) {
+	if r, ok := req.(*request); ok {
+		r.result, r.error, r.skipped = nil, nil, false
+	}
+}
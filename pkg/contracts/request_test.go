@@ -73,6 +73,17 @@ func TestNewCallRequest(t *testing.T) {
 	require.Equal(t, `{"x":10,"y":20}`, string(req.Payload()))
 }
 
+func TestNewEventRequest(t *testing.T) {
+	resourceName, valueType := "alarm", Object
+	cr := MockCommandRequest(resourceName, string(valueType))
+	cr.Attributes = SetResourceCategory(cr.Attributes, Event)
+
+	req := NewEventRequest(cr)
+	require.Equal(t, DefaultModule, req.Module())
+	require.Equal(t, resourceName, req.Resource())
+	require.Equal(t, valueType, req.ValueType())
+}
+
 func TestNewRequest(t *testing.T) {
 	resourceName, valueType := "temperature", Float32
 	cr := MockCommandRequest(resourceName, string(valueType))
@@ -96,3 +107,15 @@ func TestNewRequest(t *testing.T) {
 	req.Skip()
 	require.True(t, req.Skipped())
 }
+
+func TestReset(t *testing.T) {
+	req := NewReadRequest(MockCommandRequest("temperature", string(Float32)))
+	req.SetResult(NewSimpleResult(1.0))
+	req.Failed(fmt.Errorf("handle failed"))
+	req.Skip()
+
+	Reset(req)
+	require.Nil(t, req.Result())
+	require.NoError(t, req.Error())
+	require.False(t, req.Skipped())
+}
@@ -19,8 +19,11 @@ package contracts
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
 )
 
 // ResourceCategory indicates the category of device resource
@@ -38,8 +41,30 @@ func (c ResourceCategory) String() string {
 
 const (
 	CategoryKey   = "category"
+	TimeoutKey    = "timeout"
+	CacheTTLKey   = "cacheTTL"
+	EnumKey       = "enum"
 	DefaultModule = "default"
 	Separator     = ":"
+
+	// The filter of the readings reported asynchronously by driver. The readings of commands are only filtered if
+	// the AutoEvent filter of the agent is enabled, see vei.WithAutoEventFilter.
+	ReportOnChangeKey  = "reportOnChange"
+	DeadbandKey        = "deadband"
+	DeadbandPercentKey = "deadbandPercent"
+	MaxSilenceKey      = "maxSilence"
+
+	AggregateWindowKey    = "aggregateWindow"
+	AggregateFunctionsKey = "aggregateFunctions"
+
+	PollIntervalKey = "pollInterval"
+
+	TransformKey = "transform"
+	MaskKey      = "mask"
+	ShiftKey     = "shift"
+	BaseKey      = "base"
+	ScaleKey     = "scale"
+	OffsetKey    = "offset"
 )
 
 // GetResourceCategory get the category of resource from the request. Property is returned by default for the compatibility.
@@ -78,6 +103,19 @@ func IsEvent(req models.CommandRequest) bool {
 	return GetResourceCategory(req) == Event
 }
 
+// GetResourceDuration get the duration specified by key from the attributes, both the duration string like
+// '1.5s' and the number of milliseconds are supported. False is returned if not specified or invalid.
+func GetResourceDuration(attributes map[string]interface{}, key string) (time.Duration, bool) {
+	if attributes == nil || attributes[key] == nil {
+		return 0, false
+	}
+	duration, err := utils.CastDuration(attributes[key])
+	if err != nil || duration <= 0 {
+		return 0, false
+	}
+	return duration, true
+}
+
 // ConcatResourceName will concat the module and resource defined in vei console to edgex resource name.
 func ConcatResourceName(module string, resource string) string {
 	if module == DefaultModule {
@@ -18,6 +18,7 @@ package contracts
 
 import (
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 )
@@ -203,3 +204,27 @@ func TestSplitResourceName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceDuration(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		want       time.Duration
+		wantOK     bool
+	}{
+		{name: "nil attributes", attributes: nil},
+		{name: "not specified", attributes: map[string]interface{}{}},
+		{name: "invalid", attributes: map[string]interface{}{TimeoutKey: "abc"}},
+		{name: "negative", attributes: map[string]interface{}{TimeoutKey: -1}},
+		{name: "duration string", attributes: map[string]interface{}{TimeoutKey: "3s"}, want: time.Second * 3, wantOK: true},
+		{name: "milliseconds", attributes: map[string]interface{}{TimeoutKey: 500}, want: time.Millisecond * 500, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GetResourceDuration(tt.attributes, TimeoutKey)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("GetResourceDuration() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
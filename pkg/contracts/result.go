@@ -17,6 +17,8 @@
 package contracts
 
 import (
+	"fmt"
+	"mime"
 	"time"
 
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
@@ -36,11 +38,16 @@ type Result interface {
 	CommandValue(resourceName string, valueType string) (*models.CommandValue, error)
 }
 
+// MediaTypeTag is the tag of the Binary reading recording the media type declared by WithMediaType.
+const MediaTypeTag = "mediaType"
+
 type SimpleResult struct {
-	value  interface{}
-	origin *time.Time
-	tags   map[string]string
-	cast   bool
+	value     interface{}
+	origin    *time.Time
+	tags      map[string]string
+	cast      bool
+	transform *Transform
+	mediaType string
 }
 
 func NewSimpleResult(value interface{}) *SimpleResult {
@@ -62,6 +69,20 @@ func (r *SimpleResult) WithCast(cast bool) *SimpleResult {
 	return r
 }
 
+// WithTransform transforms the value into the engineering value before it is cast to the value type of resource,
+// the transformed value is always cast and checked against the range of the value type.
+func (r *SimpleResult) WithTransform(transform *Transform) *SimpleResult {
+	r.transform = transform
+	return r
+}
+
+// WithMediaType declares the media type of the Binary value, e.g. "image/jpeg", which is recorded in the tag
+// 'mediaType' of the reading.
+func (r *SimpleResult) WithMediaType(mediaType string) *SimpleResult {
+	r.mediaType = mediaType
+	return r
+}
+
 func (r *SimpleResult) Value() interface{} {
 	return r.value
 }
@@ -78,14 +99,44 @@ func (r *SimpleResult) Tags() map[string]string {
 }
 
 func (r *SimpleResult) CommandValue(resourceName string, valueType string) (*models.CommandValue, error) {
-	valueType = utils.Ternary(valueType == string(StringArray), string(Object), valueType)
-	cv, err := models.NewCommandValue(resourceName, valueType, r.value)
-	if err != nil && r.cast {
-		cv, err = utils.CastCommandValue(resourceName, valueType, r.value)
+	value, cast := r.value, r.cast
+	if r.transform != nil && ValueType(valueType).IsNumeric() {
+		transformed, err := r.transform.Read(value, ValueType(valueType))
+		if err != nil {
+			return nil, err
+		}
+		value, cast = transformed, true
+	}
+	// The elements of StringArray are cast before the value is mapped to Object.
+	if valueType == string(StringArray) {
+		if cast {
+			cv, err := utils.CastCommandValue(resourceName, valueType, value)
+			if err != nil {
+				return nil, err
+			}
+			value = cv.Value
+		}
+		valueType = string(Object)
+	}
+	tags := r.Tags()
+	if valueType == string(Binary) && r.mediaType != "" {
+		if _, _, err := mime.ParseMediaType(r.mediaType); err != nil {
+			return nil, fmt.Errorf("invalid media type '%s' of '%s': %v", r.mediaType, resourceName, err)
+		}
+		tags = make(map[string]string, len(r.tags)+1)
+		for k, v := range r.tags {
+			tags[k] = v
+		}
+		tags[MediaTypeTag] = r.mediaType
+	}
+	cv, err := models.NewCommandValue(resourceName, valueType, value)
+	// The binary is always cast so that its size is limited.
+	if err != nil && cast || valueType == string(Binary) {
+		cv, err = utils.CastCommandValue(resourceName, valueType, value)
 	}
 	if cv != nil {
 		cv.Origin = r.UnixNano()
-		cv.Tags = r.Tags()
+		cv.Tags = tags
 	}
 	return cv, err
 }
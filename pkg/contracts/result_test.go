@@ -24,6 +24,8 @@ import (
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/utils"
 )
 
 var (
@@ -82,6 +84,66 @@ func TestSimpleResult_CommandValue(t *testing.T) {
 	}
 }
 
+func TestSimpleResult_CommandValueWithTransform(t *testing.T) {
+	transform := &Transform{Scale: 0.1}
+	tests := []struct {
+		name      string
+		valueType string
+		value     interface{}
+		wantValue interface{}
+		wantErr   bool
+	}{
+		{name: "float", valueType: common.ValueTypeFloat32, value: int16(235), wantValue: float32(23.5)},
+		{name: "integer", valueType: common.ValueTypeUint8, value: uint16(2550), wantValue: uint8(255)},
+		{name: "overflow", valueType: common.ValueTypeUint8, value: uint16(2560), wantErr: true},
+		{name: "not numeric", valueType: common.ValueTypeFloat64, value: "text", wantErr: true},
+		{name: "not transformed", valueType: common.ValueTypeString, value: "text", wantValue: "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSimpleResult(tt.value).WithTransform(transform).CommandValue(tt.name, tt.valueType)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantValue, got.Value)
+		})
+	}
+}
+
+func TestSimpleResult_CommandValueOfArrays(t *testing.T) {
+	cv, err := NewSimpleResult([]int{1, -2}).WithCast(true).CommandValue("registers", common.ValueTypeInt16Array)
+	require.NoError(t, err)
+	require.Equal(t, []int16{1, -2}, cv.Value)
+
+	_, err = NewSimpleResult([]int{1, -2}).CommandValue("registers", common.ValueTypeInt16Array)
+	require.Error(t, err)
+
+	// StringArray is still mapped to Object after the elements are cast.
+	cv, err = NewSimpleResult([]int{1, 2}).WithCast(true).CommandValue("names", common.ValueTypeStringArray)
+	require.NoError(t, err)
+	require.Equal(t, common.ValueTypeObject, cv.Type)
+	require.Equal(t, []string{"1", "2"}, cv.Value)
+}
+
+func TestSimpleResult_CommandValueOfBinary(t *testing.T) {
+	tags := map[string]string{"camera": "front"}
+	cv, err := NewSimpleResult([]byte{0xFF, 0xD8}).WithTags(tags).WithMediaType("image/jpeg").CommandValue("image", common.ValueTypeBinary)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xFF, 0xD8}, cv.Value)
+	require.Equal(t, map[string]string{"camera": "front", MediaTypeTag: "image/jpeg"}, cv.Tags)
+	require.Len(t, tags, 1)
+
+	_, err = NewSimpleResult([]byte{0xFF, 0xD8}).WithMediaType("image/").CommandValue("image", common.ValueTypeBinary)
+	require.Error(t, err)
+
+	defer func(size int) { utils.MaxBinarySize = size }(utils.MaxBinarySize)
+	utils.MaxBinarySize = 1
+	_, err = NewSimpleResult([]byte{0xFF, 0xD8}).CommandValue("image", common.ValueTypeBinary)
+	require.Error(t, err)
+}
+
 func TestNativeResult_Value(t *testing.T) {
 	native, _ := models.NewCommandValue("", common.ValueTypeString, "string-value")
 	result := NewNativeResult(native)
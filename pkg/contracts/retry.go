@@ -0,0 +1,110 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	RetryMaxAttemptsKey = "retryMaxAttempts"
+	RetryBackoffKey     = "retryBackoff"
+)
+
+const (
+	DefaultRetryBackoff    = time.Millisecond * 100
+	DefaultRetryMaxBackoff = time.Second * 5
+	DefaultRetryMultiplier = 2.0
+)
+
+// DefaultRetryableKinds are the kinds of error which are retryable if no one is specified in the RetryPolicy.
+var DefaultRetryableKinds = []ErrorKind{ConnectionFailed, ConnectionTimeout, NetworkUnreachable, ReadTimeout, WriteTimeout}
+
+// RetryPolicy defines how the failed requests are retried, the attempts and backoff can be overridden
+// for each resource through the attributes 'retryMaxAttempts' and 'retryBackoff'.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one, no retry if less than 2.
+	MaxAttempts int
+	// InitialBackoff is the backoff before the first retry, 100ms by default.
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper limit of the backoff, 5s by default.
+	MaxBackoff time.Duration
+	// Multiplier is the factor to multiply the backoff after each retry, 2 by default.
+	Multiplier float64
+	// RetryableKinds are the kinds of error which are retryable, DefaultRetryableKinds is used if empty.
+	RetryableKinds []ErrorKind
+}
+
+// Attempts returns the maximum number of attempts for the request.
+func (p RetryPolicy) Attempts(req BaseRequest) int {
+	attributes := req.Attributes()
+	if attributes != nil && attributes[RetryMaxAttemptsKey] != nil {
+		if attempts, err := cast.ToIntE(attributes[RetryMaxAttemptsKey]); err == nil {
+			return attempts
+		}
+	}
+	return p.MaxAttempts
+}
+
+// Backoff returns the backoff before the specified retry of the request, the retry starts from 1.
+func (p RetryPolicy) Backoff(req BaseRequest, retry int) time.Duration {
+	backoff, ok := GetResourceDuration(req.Attributes(), RetryBackoffKey)
+	if !ok {
+		backoff = p.InitialBackoff
+	}
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = DefaultRetryMultiplier
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+
+	backoff = time.Duration(float64(backoff) * math.Pow(multiplier, float64(retry-1)))
+	if backoff > maxBackoff || backoff <= 0 {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// Retryable checks whether the error is retryable according to its kind, the errors other than Error are
+// retryable if they implement 'Retryable() bool' and report so.
+func (p RetryPolicy) Retryable(err error) bool {
+	kind, ok := KindOf(err)
+	if !ok {
+		var r interface{ Retryable() bool }
+		return errors.As(err, &r) && r.Retryable()
+	}
+	kinds := p.RetryableKinds
+	if len(kinds) == 0 {
+		return kind.Retryable()
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
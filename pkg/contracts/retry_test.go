@@ -0,0 +1,80 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Attempts(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+	req := NewReadRequest(models.CommandRequest{})
+	require.Equal(t, 3, policy.Attempts(req))
+
+	req = NewReadRequest(models.CommandRequest{Attributes: map[string]interface{}{RetryMaxAttemptsKey: "5"}})
+	require.Equal(t, 5, policy.Attempts(req))
+
+	req = NewReadRequest(models.CommandRequest{Attributes: map[string]interface{}{RetryMaxAttemptsKey: "many"}})
+	require.Equal(t, 3, policy.Attempts(req))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	req := NewReadRequest(models.CommandRequest{})
+
+	policy := RetryPolicy{}
+	require.Equal(t, DefaultRetryBackoff, policy.Backoff(req, 1))
+	require.Equal(t, DefaultRetryBackoff*2, policy.Backoff(req, 2))
+	require.Equal(t, DefaultRetryMaxBackoff, policy.Backoff(req, 100))
+
+	policy = RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Second * 10, Multiplier: 3}
+	require.Equal(t, time.Second, policy.Backoff(req, 1))
+	require.Equal(t, time.Second*9, policy.Backoff(req, 3))
+	require.Equal(t, time.Second*10, policy.Backoff(req, 4))
+
+	req = NewReadRequest(models.CommandRequest{Attributes: map[string]interface{}{RetryBackoffKey: "10ms"}})
+	require.Equal(t, time.Millisecond*30, policy.Backoff(req, 2))
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	policy := RetryPolicy{}
+	require.True(t, policy.Retryable(NewErrorWithReason(ConnectionTimeout, "")))
+	require.True(t, policy.Retryable(NewErrorWithReason(ReadTimeout, "")))
+	require.False(t, policy.Retryable(NewErrorWithReason(AuthFailed, "")))
+	require.False(t, policy.Retryable(errors.New("raw")))
+	require.True(t, policy.Retryable(fmt.Errorf("wrapped: %w", retryableError(true))))
+	require.False(t, policy.Retryable(retryableError(false)))
+
+	policy = RetryPolicy{RetryableKinds: []ErrorKind{ReadError}}
+	require.True(t, policy.Retryable(NewErrorWithReason(ReadError, "")))
+	require.False(t, policy.Retryable(NewErrorWithReason(ReadTimeout, "")))
+}
+
+type retryableError bool
+
+func (e retryableError) Error() string {
+	return "retryable"
+}
+
+func (e retryableError) Retryable() bool {
+	return bool(e)
+}
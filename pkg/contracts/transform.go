@@ -0,0 +1,203 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/spf13/cast"
+)
+
+// Transform converts the raw values of a resource into the engineering values when reading, and the engineering
+// values back into the raw values when writing. The reading is masked, shifted, raised to the base, scaled and
+// offset in order, the same as the DataTransform of EdgeX, and the writing is inverted in the reverse order. Like
+// EdgeX, the mask and shift only apply to the unsigned integer types.
+type Transform struct {
+	Mask   uint64  // zero means no mask
+	Shift  int64   // positive to shift left, and negative to shift right
+	Base   float64 // zero means no base
+	Scale  float64 // zero means no scale
+	Offset float64
+}
+
+// NewTransform parses the transform of the resource from its properties and attributes, the attributes 'mask',
+// 'shift', 'base', 'scale' and 'offset' take precedence over the properties with the same names. Nil is returned
+// if no transform is defined, or the attribute 'transform' is false to opt out of the transform of SDK.
+func NewTransform(properties models.ResourceProperties, attributes map[string]interface{}) (*Transform, error) {
+	if attributes != nil && attributes[TransformKey] != nil {
+		enabled, err := cast.ToBoolE(attributes[TransformKey])
+		if err != nil {
+			return nil, NewErrorWithReason(AttributeParseFailed, fmt.Sprintf("invalid %s: %v", TransformKey, err))
+		}
+		if !enabled {
+			return nil, nil
+		}
+	}
+	lookup := func(key string, property string) interface{} {
+		if attributes != nil && attributes[key] != nil {
+			return attributes[key]
+		}
+		if property == "" {
+			return nil
+		}
+		return property
+	}
+
+	t, err := &Transform{}, error(nil)
+	if v := lookup(MaskKey, properties.Mask); v != nil {
+		if t.Mask, err = cast.ToUint64E(v); err != nil {
+			return nil, NewErrorWithReason(AttributeParseFailed, fmt.Sprintf("invalid %s: %v", MaskKey, err))
+		}
+	}
+	if v := lookup(ShiftKey, properties.Shift); v != nil {
+		if t.Shift, err = cast.ToInt64E(v); err != nil {
+			return nil, NewErrorWithReason(AttributeParseFailed, fmt.Sprintf("invalid %s: %v", ShiftKey, err))
+		}
+	}
+	if v := lookup(BaseKey, properties.Base); v != nil {
+		if t.Base, err = cast.ToFloat64E(v); err != nil {
+			return nil, NewErrorWithReason(AttributeParseFailed, fmt.Sprintf("invalid %s: %v", BaseKey, err))
+		}
+	}
+	if v := lookup(ScaleKey, properties.Scale); v != nil {
+		if t.Scale, err = cast.ToFloat64E(v); err != nil {
+			return nil, NewErrorWithReason(AttributeParseFailed, fmt.Sprintf("invalid %s: %v", ScaleKey, err))
+		}
+	}
+	if v := lookup(OffsetKey, properties.Offset); v != nil {
+		if t.Offset, err = cast.ToFloat64E(v); err != nil {
+			return nil, NewErrorWithReason(AttributeParseFailed, fmt.Sprintf("invalid %s: %v", OffsetKey, err))
+		}
+	}
+	if t.Scale == 1 {
+		t.Scale = 0
+	}
+	if *t == (Transform{}) {
+		return nil, nil
+	}
+	return t, nil
+}
+
+// Read transforms the raw value into the engineering value of the value type, the value is returned unchanged if
+// the value type is not numeric. The mask and shift are skipped unless the value type is unsigned, and the results
+// of integer types are rounded.
+func (t *Transform) Read(value interface{}, valueType ValueType) (interface{}, error) {
+	if t == nil || !valueType.IsNumeric() {
+		return value, nil
+	}
+	if (t.Mask != 0 || t.Shift != 0) && valueType.IsUnsigned() {
+		raw, err := cast.ToUint64E(value)
+		if err != nil {
+			return nil, fmt.Errorf("mask or shift %v: %v", value, err)
+		}
+		if t.Mask != 0 {
+			raw &= t.Mask
+		}
+		if t.Shift > 0 {
+			raw <<= t.Shift
+		} else {
+			raw >>= -t.Shift
+		}
+		value = raw
+	}
+	if t.Base == 0 && t.Scale == 0 && t.Offset == 0 {
+		return value, nil
+	}
+	f, err := cast.ToFloat64E(value)
+	if err != nil {
+		return nil, fmt.Errorf("transform %v: %v", value, err)
+	}
+	if t.Base != 0 {
+		f = math.Pow(t.Base, f)
+	}
+	if t.Scale != 0 {
+		f *= t.Scale
+	}
+	return round(f+t.Offset, valueType), nil
+}
+
+// Write transforms the engineering value of the value type back into the raw value, the value is returned
+// unchanged if the value type is not numeric. The offset, scale and base are inverted in order, and the results of
+// integer types are rounded. Then the shift of unsigned types is inverted, and the value which cannot be shifted
+// back exactly or does not fit in the bits of mask is rejected, since it could never be read from the device.
+func (t *Transform) Write(value interface{}, valueType ValueType) (interface{}, error) {
+	if t == nil || !valueType.IsNumeric() {
+		return value, nil
+	}
+	if t.Base != 0 || t.Scale != 0 || t.Offset != 0 {
+		f, err := cast.ToFloat64E(value)
+		if err != nil {
+			return nil, fmt.Errorf("transform %v: %v", value, err)
+		}
+		f -= t.Offset
+		if t.Scale != 0 {
+			f /= t.Scale
+		}
+		if t.Base != 0 {
+			f = math.Log(f) / math.Log(t.Base)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("transform %v: the raw value is %v", value, f)
+		}
+		value = round(f, valueType)
+	}
+	if (t.Mask == 0 && t.Shift == 0) || !valueType.IsUnsigned() {
+		return value, nil
+	}
+	return t.unmask(value)
+}
+
+// unmask inverts the shift of the value which must be an unsigned integer, and checks it against the mask.
+func (t *Transform) unmask(value interface{}) (uint64, error) {
+	var v uint64
+	switch value.(type) {
+	case float32, float64:
+		f := cast.ToFloat64(value)
+		if f < 0 || f >= 1<<64 || f != math.Trunc(f) {
+			return 0, fmt.Errorf("mask or shift %v: not an unsigned integer", value)
+		}
+		v = uint64(f)
+	default:
+		var err error
+		if v, err = cast.ToUint64E(value); err != nil {
+			return 0, fmt.Errorf("mask or shift %v: %v", value, err)
+		}
+	}
+	raw := v
+	if t.Shift > 0 {
+		if raw = v >> t.Shift; raw<<t.Shift != v {
+			return 0, fmt.Errorf("value %v can not be shifted by %d", value, t.Shift)
+		}
+	} else if t.Shift < 0 {
+		if raw = v << -t.Shift; raw>>-t.Shift != v {
+			return 0, fmt.Errorf("value %v can not be shifted by %d", value, t.Shift)
+		}
+	}
+	if t.Mask != 0 && raw&^t.Mask != 0 {
+		return 0, fmt.Errorf("value %v exceeds the mask %#x", value, t.Mask)
+	}
+	return raw, nil
+}
+
+func round(f float64, valueType ValueType) float64 {
+	if valueType == Float32 || valueType == Float64 {
+		return f
+	}
+	return math.Round(f)
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransform(t *testing.T) {
+	transform, err := NewTransform(models.ResourceProperties{Scale: "1.0", Offset: "0.0", Base: "0", Mask: "0", Shift: "0"}, nil)
+	require.NoError(t, err)
+	require.Nil(t, transform)
+
+	transform, err = NewTransform(models.ResourceProperties{Scale: "0.1", Offset: "5"}, map[string]interface{}{
+		ScaleKey: 0.5,
+		MaskKey:  "0xFF00",
+		ShiftKey: -8,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &Transform{Mask: 0xFF00, Shift: -8, Scale: 0.5, Offset: 5}, transform)
+
+	transform, err = NewTransform(models.ResourceProperties{Scale: "0.1"}, map[string]interface{}{TransformKey: "false"})
+	require.NoError(t, err)
+	require.Nil(t, transform)
+
+	for _, attributes := range []map[string]interface{}{
+		{TransformKey: "maybe"},
+		{MaskKey: "-1"},
+		{ShiftKey: "left"},
+		{BaseKey: "e"},
+		{ScaleKey: "x"},
+		{OffsetKey: []int{1}},
+	} {
+		_, err = NewTransform(models.ResourceProperties{}, attributes)
+		kind, _ := KindOf(err)
+		require.Equal(t, AttributeParseFailed, kind)
+	}
+}
+
+func TestTransform_Read(t *testing.T) {
+	transform := &Transform{Scale: 0.1, Offset: -40}
+	value, err := transform.Read(int16(635), Float32)
+	require.NoError(t, err)
+	require.InDelta(t, 23.5, value, 1e-9)
+
+	value, err = transform.Read(uint16(636), Int16)
+	require.NoError(t, err)
+	require.Equal(t, float64(24), value)
+
+	value, err = (&Transform{Mask: 0x0F00, Shift: -8}).Read(uint16(0x1A2B), Uint8)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x0A), value)
+
+	value, err = (&Transform{Base: 2}).Read(10, Uint16)
+	require.NoError(t, err)
+	require.Equal(t, float64(1024), value)
+
+	value, err = transform.Read("text", String)
+	require.NoError(t, err)
+	require.Equal(t, "text", value)
+
+	// the mask and shift are skipped for the signed and float types
+	value, err = (&Transform{Mask: 0xFF, Shift: 2}).Read(int16(-300), Int16)
+	require.NoError(t, err)
+	require.Equal(t, int16(-300), value)
+	value, err = (&Transform{Mask: 0xFF, Scale: 0.5}).Read(int16(-300), Int16)
+	require.NoError(t, err)
+	require.Equal(t, float64(-150), value)
+	value, err = (&Transform{Shift: -1}).Read(float32(2.5), Float32)
+	require.NoError(t, err)
+	require.Equal(t, float32(2.5), value)
+	_, err = (&Transform{Mask: 0xFF}).Read(-1, Uint8)
+	require.Error(t, err)
+	_, err = transform.Read("text", Float32)
+	require.Error(t, err)
+}
+
+func TestTransform_Write(t *testing.T) {
+	transform := &Transform{Scale: 0.1, Offset: -40}
+	for _, raw := range []int16{0, 635, 1000} {
+		value, err := transform.Read(raw, Float64)
+		require.NoError(t, err)
+		value, err = transform.Write(value, Int16)
+		require.NoError(t, err)
+		require.Equal(t, float64(raw), value)
+	}
+
+	value, err := (&Transform{Base: 10}).Write(1000.0, Float64)
+	require.NoError(t, err)
+	require.InDelta(t, 3, value, 1e-9)
+
+	// the mask and shift are inverted, and the values which can not be read back are rejected
+	value, err = (&Transform{Mask: 0xFF}).Write(int32(-7), Int32)
+	require.NoError(t, err)
+	require.Equal(t, int32(-7), value)
+	masked := &Transform{Mask: 0x0F00, Shift: -8}
+	value, err = masked.Write(uint8(0x0A), Uint8)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x0A00), value)
+	value, err = masked.Read(value, Uint8)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x0A), value)
+	value, err = (&Transform{Shift: 2, Scale: 0.5}).Write(float32(6), Uint16)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), value)
+	for _, param := range []interface{}{uint8(0x1A), int8(-1), float32(1.5)} {
+		_, err = masked.Write(param, Uint8)
+		require.Error(t, err)
+	}
+	_, err = (&Transform{Shift: 2}).Write(uint16(7), Uint16)
+	require.ErrorContains(t, err, "shifted")
+
+	_, err = (&Transform{Base: 10}).Write(-1, Float64)
+	require.Error(t, err)
+	_, err = transform.Write("text", Float64)
+	require.Error(t, err)
+}
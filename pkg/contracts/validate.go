@@ -0,0 +1,200 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+)
+
+// ValidateWriteParameter checks the parameter against the constraints of the resource defined in the device
+// profile, which are the ReadWrite permission, the Minimum and Maximum of numeric values, the Mask and Shift of
+// unsigned integers, and the allowed values listed in the attribute 'enum'. The Minimum and Maximum are only
+// checked when no Scale, Offset or Base is defined, since the parameter has been transformed into the raw value
+// of device by then. An error of ParameterParseFailed is returned if any constraint is broken.
+func ValidateWriteParameter(resource models.DeviceResource, param *sdkmodels.CommandValue) error {
+	if param == nil {
+		return NewErrorWithReason(ParameterParseFailed, fmt.Sprintf("missing parameter of resource '%s'", resource.Name))
+	}
+	properties := resource.Properties
+	if properties.ReadWrite == common.ReadWrite_R {
+		return NewErrorWithReason(ParameterParseFailed, fmt.Sprintf("resource '%s' is read-only", resource.Name))
+	}
+	if err := validateRange(properties, param.Value); err != nil {
+		return NewErrorWithReason(ParameterParseFailed, fmt.Sprintf("resource '%s': %v", resource.Name, err))
+	}
+	if err := validateMask(properties, param.Value); err != nil {
+		return NewErrorWithReason(ParameterParseFailed, fmt.Sprintf("resource '%s': %v", resource.Name, err))
+	}
+	if err := validateEnum(resource.Attributes, param); err != nil {
+		return NewErrorWithReason(ParameterParseFailed, fmt.Sprintf("resource '%s': %v", resource.Name, err))
+	}
+	return nil
+}
+
+func isDefined(value string, fallback string) bool {
+	return value != "" && value != fallback
+}
+
+func validateRange(properties models.ResourceProperties, value interface{}) error {
+	if properties.Minimum == "" && properties.Maximum == "" {
+		return nil
+	}
+	if isDefined(properties.Scale, "1") || isDefined(properties.Offset, "0") || isDefined(properties.Base, "0") {
+		return nil
+	}
+
+	var minimum, maximum *big.Float
+	var ok bool
+	if properties.Minimum != "" {
+		if minimum, ok = new(big.Float).SetString(properties.Minimum); !ok {
+			return fmt.Errorf("invalid minimum '%s'", properties.Minimum)
+		}
+	}
+	if properties.Maximum != "" {
+		if maximum, ok = new(big.Float).SetString(properties.Maximum); !ok {
+			return fmt.Errorf("invalid maximum '%s'", properties.Maximum)
+		}
+	}
+
+	return eachNumber(value, func(number *big.Float, raw interface{}) error {
+		if number == nil {
+			return fmt.Errorf("value %v is not a number", raw)
+		}
+		if minimum != nil && number.Cmp(minimum) < 0 {
+			return fmt.Errorf("value %v is less than the minimum %s", raw, properties.Minimum)
+		}
+		if maximum != nil && number.Cmp(maximum) > 0 {
+			return fmt.Errorf("value %v is greater than the maximum %s", raw, properties.Maximum)
+		}
+		return nil
+	})
+}
+
+// eachNumber calls fn with each number of the numeric value or array, values of other types are ignored.
+func eachNumber(value interface{}, fn func(number *big.Float, raw interface{}) error) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice {
+		for i := 0; i < rv.Len(); i++ {
+			if err := eachNumber(rv.Index(i).Interface(), fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fn(new(big.Float).SetInt64(rv.Int()), value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fn(new(big.Float).SetUint64(rv.Uint()), value)
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(rv.Float()) {
+			return fn(nil, value)
+		}
+		return fn(new(big.Float).SetFloat64(rv.Float()), value)
+	default:
+		return nil
+	}
+}
+
+// validateMask checks whether the unsigned integer fits in the bits of mask after shifted, which is the inverse
+// of the mask and shift applied to the readings.
+func validateMask(properties models.ResourceProperties, value interface{}) error {
+	if !isDefined(properties.Mask, "0") {
+		return nil
+	}
+	mask, err := strconv.ParseUint(properties.Mask, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid mask '%s'", properties.Mask)
+	}
+	var shift int64
+	if isDefined(properties.Shift, "0") {
+		if shift, err = strconv.ParseInt(properties.Shift, 10, 8); err != nil {
+			return fmt.Errorf("invalid shift '%s'", properties.Shift)
+		}
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil
+	}
+	v, raw := rv.Uint(), rv.Uint()
+	if shift > 0 {
+		raw = v >> shift
+		if raw<<shift != v {
+			return fmt.Errorf("value %v can not be shifted by %d", value, shift)
+		}
+	} else if shift < 0 {
+		raw = v << -shift
+		if raw>>-shift != v {
+			return fmt.Errorf("value %v can not be shifted by %d", value, shift)
+		}
+	}
+	if raw&^mask != 0 {
+		return fmt.Errorf("value %v exceeds the mask %s", value, properties.Mask)
+	}
+	return nil
+}
+
+// validateEnum checks whether the value is one of the allowed values listed in the attribute 'enum', which can
+// be either a list or a comma-separated string.
+func validateEnum(attributes map[string]interface{}, param *sdkmodels.CommandValue) error {
+	if attributes == nil || attributes[EnumKey] == nil {
+		return nil
+	}
+
+	allowed := make([]string, 0)
+	switch enum := attributes[EnumKey].(type) {
+	case string:
+		for _, s := range strings.Split(enum, ",") {
+			allowed = append(allowed, strings.TrimSpace(s))
+		}
+	case []interface{}:
+		for _, v := range enum {
+			allowed = append(allowed, fmt.Sprintf("%v", v))
+		}
+	case []string:
+		allowed = append(allowed, enum...)
+	default:
+		return fmt.Errorf("invalid enum '%v'", enum)
+	}
+
+	value := fmt.Sprintf("%v", param.Value)
+	for _, s := range allowed {
+		if s == value {
+			return nil
+		}
+		// the numbers are compared by value, e.g. '1.0' equals to '1'
+		if a, err := strconv.ParseFloat(s, 64); err == nil {
+			if b, err := strconv.ParseFloat(value, 64); err == nil && a == b {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("value %s is not one of %v", value, allowed)
+}
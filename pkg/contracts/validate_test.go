@@ -0,0 +1,77 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracts
+
+import (
+	"math"
+	"testing"
+
+	sdkmodels "github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v2/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWriteParameter(t *testing.T) {
+	param := func(valueType string, value interface{}) *sdkmodels.CommandValue {
+		cv, err := sdkmodels.NewCommandValue("resource", valueType, value)
+		require.NoError(t, err)
+		return cv
+	}
+	tests := []struct {
+		name       string
+		properties models.ResourceProperties
+		attributes map[string]interface{}
+		param      *sdkmodels.CommandValue
+		valid      bool
+	}{
+		{name: "no constraint", param: param(common.ValueTypeInt32, int32(1)), valid: true},
+		{name: "missing param", param: nil, valid: false},
+		{name: "read only", properties: models.ResourceProperties{ReadWrite: common.ReadWrite_R}, param: param(common.ValueTypeInt32, int32(1)), valid: false},
+		{name: "writable", properties: models.ResourceProperties{ReadWrite: common.ReadWrite_RW}, param: param(common.ValueTypeInt32, int32(1)), valid: true},
+		{name: "in range", properties: models.ResourceProperties{Minimum: "-10", Maximum: "10.5"}, param: param(common.ValueTypeFloat32, float32(10.5)), valid: true},
+		{name: "below minimum", properties: models.ResourceProperties{Minimum: "-10"}, param: param(common.ValueTypeInt8, int8(-11)), valid: false},
+		{name: "above maximum", properties: models.ResourceProperties{Maximum: "18446744073709551614"}, param: param(common.ValueTypeUint64, uint64(math.MaxUint64)), valid: false},
+		{name: "NaN", properties: models.ResourceProperties{Maximum: "10"}, param: param(common.ValueTypeFloat64, math.NaN()), valid: false},
+		{name: "array out of range", properties: models.ResourceProperties{Maximum: "10"}, param: param(common.ValueTypeInt32Array, []int32{1, 11}), valid: false},
+		{name: "transformed", properties: models.ResourceProperties{Maximum: "10", Scale: "0.1"}, param: param(common.ValueTypeInt32, int32(100)), valid: true},
+		{name: "invalid maximum", properties: models.ResourceProperties{Maximum: "ten"}, param: param(common.ValueTypeInt32, int32(1)), valid: false},
+		{name: "not a number", properties: models.ResourceProperties{Maximum: "10"}, param: param(common.ValueTypeString, "11"), valid: true},
+		{name: "in mask", properties: models.ResourceProperties{Mask: "240", Shift: "-4"}, param: param(common.ValueTypeUint8, uint8(15)), valid: true},
+		{name: "exceed mask", properties: models.ResourceProperties{Mask: "240", Shift: "-4"}, param: param(common.ValueTypeUint8, uint8(16)), valid: false},
+		{name: "lost by shift", properties: models.ResourceProperties{Mask: "15", Shift: "1"}, param: param(common.ValueTypeUint8, uint8(3)), valid: false},
+		{name: "mask of signed", properties: models.ResourceProperties{Mask: "15"}, param: param(common.ValueTypeInt8, int8(16)), valid: true},
+		{name: "in enum", attributes: map[string]interface{}{EnumKey: "on, off"}, param: param(common.ValueTypeString, "off"), valid: true},
+		{name: "not in enum", attributes: map[string]interface{}{EnumKey: "on, off"}, param: param(common.ValueTypeString, "auto"), valid: false},
+		{name: "number in enum", attributes: map[string]interface{}{EnumKey: []interface{}{1.0, 2.0}}, param: param(common.ValueTypeUint16, uint16(2)), valid: true},
+		{name: "invalid enum", attributes: map[string]interface{}{EnumKey: 1}, param: param(common.ValueTypeUint16, uint16(1)), valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := models.DeviceResource{Name: "resource", Properties: tt.properties, Attributes: tt.attributes}
+			err := ValidateWriteParameter(resource, tt.param)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			kind, ok := KindOf(err)
+			require.True(t, ok)
+			require.Equal(t, ParameterParseFailed, kind)
+		})
+	}
+}
@@ -20,6 +20,21 @@ import (
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 )
 
+// OverflowPolicy is the policy to handle the async values reported when the buffer is full.
+type OverflowPolicy string
+
+const (
+	// Block waits for the space of buffer until the timeout exceeds, the values are dropped then.
+	Block OverflowPolicy = "block"
+	// DropOldest drops the oldest values in the buffer to make room for the new ones.
+	DropOldest OverflowPolicy = "dropOldest"
+	// DropNewest drops the new values.
+	DropNewest OverflowPolicy = "dropNewest"
+	// Coalesce replaces the values of the same device and source in the buffer with the new ones, the oldest
+	// values are dropped if there are none.
+	Coalesce OverflowPolicy = "coalesce"
+)
+
 // AsyncValues is the struct for sending Device readings asynchronously via ProtocolDrivers
 type AsyncValues struct {
 	DeviceName    string
@@ -34,3 +49,17 @@ func (v *AsyncValues) Transform() *models.AsyncValues {
 		CommandValues: v.CommandValues,
 	}
 }
+
+// NewEventValues wraps the payload of the event into AsyncValues, the source name and the command value
+// are both named by the module and resource of the event.
+func NewEventValues(deviceName string, req EventRequest, result Result) (*AsyncValues, error) {
+	cv, err := result.CommandValue(req.Native().DeviceResourceName, req.Native().Type)
+	if err != nil {
+		return nil, err
+	}
+	return &AsyncValues{
+		DeviceName:    deviceName,
+		SourceName:    req.Native().DeviceResourceName,
+		CommandValues: []*models.CommandValue{cv},
+	}, nil
+}
@@ -42,3 +42,18 @@ func TestAsyncValues_Transform(t *testing.T) {
 	v2, _ = json.Marshal(transformed)
 	require.Equal(t, v1, v2)
 }
+
+func TestNewEventValues(t *testing.T) {
+	cr := models.CommandRequest{DeviceResourceName: "security:alarm", Type: common.ValueTypeObject}
+	req := NewEventRequest(cr)
+
+	values, err := NewEventValues("device", req, NewSimpleResult(map[string]interface{}{"level": 1}))
+	require.NoError(t, err)
+	require.Equal(t, "device", values.DeviceName)
+	require.Equal(t, "security:alarm", values.SourceName)
+	require.Len(t, values.CommandValues, 1)
+	require.Equal(t, "security:alarm", values.CommandValues[0].DeviceResourceName)
+
+	_, err = NewEventValues("device", req, NewSimpleResult(nil))
+	require.Error(t, err)
+}
@@ -51,3 +51,23 @@ const (
 func (t ValueType) String() string {
 	return string(t)
 }
+
+// IsNumeric returns whether the value type is an integer or float type.
+func (t ValueType) IsNumeric() bool {
+	switch t {
+	case Uint8, Uint16, Uint32, Uint64, Int8, Int16, Int32, Int64, Float32, Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsUnsigned returns whether the value type is an unsigned integer type.
+func (t ValueType) IsUnsigned() bool {
+	switch t {
+	case Uint8, Uint16, Uint32, Uint64:
+		return true
+	default:
+		return false
+	}
+}
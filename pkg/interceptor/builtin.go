@@ -0,0 +1,120 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interceptor
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+// Logging logs the invocations at debug level, and the failed ones at warn level.
+func Logging(log logger.Logger) Interceptor {
+	if log == nil {
+		log = logger.D
+	}
+	return func(inv *Invocation, next Invoker) error {
+		start := time.Now()
+		err := next(inv)
+		failed := 0
+		for _, req := range inv.Requests() {
+			if req.Error() != nil {
+				failed++
+			}
+		}
+		if err != nil {
+			log.Warnf("%s of device '%s' failed, size: %d, elapsed: %v, error: %v",
+				inv.Operation, inv.Device.Name, inv.Size(), time.Since(start), err)
+		} else {
+			log.Debugf("%s of device '%s' finished, size: %d, failed: %d, elapsed: %v",
+				inv.Operation, inv.Device.Name, inv.Size(), failed, time.Since(start))
+		}
+		return err
+	}
+}
+
+// Metrics records the latency and the errors of each operation into the registry, which are named as
+// 'Driver<Operation>Latency' and 'Driver<Operation>Errors'. The metrics in the default registry are published
+// through the telemetry of EdgeX if they are enabled in the configuration.
+func Metrics(registry metrics.Registry) Interceptor {
+	if registry == nil {
+		registry = metrics.DefaultRegistry
+	}
+	timers := make(map[Operation]metrics.Timer)
+	counters := make(map[Operation]metrics.Counter)
+	for _, op := range []Operation{Read, Write, Call, Report} {
+		timers[op] = metrics.GetOrRegisterTimer(fmt.Sprintf("Driver%sLatency", op), registry)
+		counters[op] = metrics.GetOrRegisterCounter(fmt.Sprintf("Driver%sErrors", op), registry)
+	}
+
+	return func(inv *Invocation, next Invoker) error {
+		start := time.Now()
+		err := next(inv)
+		if timer, ok := timers[inv.Operation]; ok {
+			timer.UpdateSince(start)
+		}
+		errors := 0
+		for _, req := range inv.Requests() {
+			if req.Error() != nil {
+				errors++
+			}
+		}
+		if err != nil {
+			errors = inv.Size()
+		}
+		if counter, ok := counters[inv.Operation]; ok && errors > 0 {
+			counter.Inc(int64(errors))
+		}
+		return err
+	}
+}
+
+// Recovery recovers the panic raised by the inner interceptors and returns it as the DriverPanic error, the requests
+// left without result or error are failed with it too. The panics are handled by the Recover of the invocation if
+// set, otherwise the stack is logged for troubleshooting. The panics raised by the driver itself are always
+// recovered by the agent, so this is only needed for the interceptors.
+func Recovery(log logger.Logger) Interceptor {
+	if log == nil {
+		log = logger.D
+	}
+	return func(inv *Invocation, next Invoker) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if inv.Recover != nil {
+				err = inv.Recover(r)
+			} else {
+				log.Errorf("panic in %s of device '%s': %v\n%s", inv.Operation, inv.Device.Name, r, debug.Stack())
+				err = contracts.NewErrorWithReason(contracts.DriverPanic,
+					fmt.Sprintf("panic in %s of device '%s': %v", inv.Operation, inv.Device.Name, r))
+			}
+			for _, req := range inv.Requests() {
+				if !req.Skipped() && req.Error() == nil && req.Result() == nil {
+					req.Failed(err)
+				}
+			}
+		}()
+		return next(inv)
+	}
+}
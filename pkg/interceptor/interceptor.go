@@ -0,0 +1,94 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interceptor
+
+import (
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+)
+
+// Operation is the kind of the driver invocation being intercepted.
+type Operation string
+
+const (
+	Read   Operation = "Read"
+	Write  Operation = "Write"
+	Call   Operation = "Call"
+	Report Operation = "Report"
+)
+
+func (o Operation) String() string {
+	return string(o)
+}
+
+// Invocation describes an invocation of driver. Only the requests of the operation are set, and the Values is
+// only set for the reports of async values. After the invocation, the results and errors can be found in the
+// requests.
+type Invocation struct {
+	Operation     Operation
+	Device        *contracts.Device
+	ReadRequests  []contracts.ReadRequest
+	WriteRequests []contracts.WriteRequest
+	CallRequests  []contracts.CallRequest
+	Values        *contracts.AsyncValues
+	// Recover converts the panic recovered by the Recovery interceptor into the error, which is set by the agent to
+	// count the panic of the device like the ones raised by driver. The panic is only logged if nil.
+	Recover func(r interface{}) error
+}
+
+// Requests returns the requests of the invocation regardless of the operation.
+func (inv *Invocation) Requests() []contracts.BaseRequest {
+	reqs := make([]contracts.BaseRequest, 0, len(inv.ReadRequests)+len(inv.WriteRequests)+len(inv.CallRequests))
+	for _, req := range inv.ReadRequests {
+		reqs = append(reqs, req)
+	}
+	for _, req := range inv.WriteRequests {
+		reqs = append(reqs, req)
+	}
+	for _, req := range inv.CallRequests {
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// Size returns the number of requests or values of the invocation.
+func (inv *Invocation) Size() int {
+	if inv.Values != nil {
+		return len(inv.Values.CommandValues)
+	}
+	return len(inv.ReadRequests) + len(inv.WriteRequests) + len(inv.CallRequests)
+}
+
+// Invoker performs the invocation.
+type Invoker func(inv *Invocation) error
+
+// Interceptor intercepts the invocation, it's able to inspect or modify the invocation before and after calling
+// next, or short-circuit the invocation by returning without calling next.
+type Interceptor func(inv *Invocation, next Invoker) error
+
+// Chain wraps the invoker with the interceptors, the first interceptor is the outermost one.
+func Chain(invoker Invoker, interceptors ...Interceptor) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		if interceptor == nil {
+			continue
+		}
+		invoker = func(inv *Invocation) error {
+			return interceptor(inv, next)
+		}
+	}
+	return invoker
+}
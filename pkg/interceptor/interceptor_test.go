@@ -0,0 +1,115 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interceptor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/require"
+
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/logger"
+)
+
+func newInvocation() *Invocation {
+	return &Invocation{
+		Operation: Read,
+		Device:    contracts.WrapDevice("device", nil),
+		ReadRequests: []contracts.ReadRequest{
+			contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "temperature"}),
+			contracts.NewReadRequest(models.CommandRequest{DeviceResourceName: "humidity"}),
+		},
+	}
+}
+
+func TestChain(t *testing.T) {
+	trace := make([]string, 0)
+	record := func(name string) Interceptor {
+		return func(inv *Invocation, next Invoker) error {
+			trace = append(trace, name+"-before")
+			err := next(inv)
+			trace = append(trace, name+"-after")
+			return err
+		}
+	}
+	invoker := Chain(func(inv *Invocation) error {
+		trace = append(trace, "driver")
+		return nil
+	}, record("first"), nil, record("second"))
+
+	require.NoError(t, invoker(newInvocation()))
+	require.Equal(t, []string{"first-before", "second-before", "driver", "second-after", "first-after"}, trace)
+
+	// short-circuit
+	invoked := false
+	invoker = Chain(func(inv *Invocation) error {
+		invoked = true
+		return nil
+	}, func(inv *Invocation, next Invoker) error {
+		return errors.New("rejected")
+	})
+	require.EqualError(t, invoker(newInvocation()), "rejected")
+	require.False(t, invoked)
+}
+
+func TestInvocation(t *testing.T) {
+	inv := newInvocation()
+	require.Len(t, inv.Requests(), 2)
+	require.Equal(t, 2, inv.Size())
+
+	inv = &Invocation{Operation: Report, Values: &contracts.AsyncValues{CommandValues: []*models.CommandValue{{}}}}
+	require.Len(t, inv.Requests(), 0)
+	require.Equal(t, 1, inv.Size())
+}
+
+func TestBuiltin(t *testing.T) {
+	registry := metrics.NewRegistry()
+	invoker := Chain(func(inv *Invocation) error {
+		inv.ReadRequests[0].Failed(errors.New("read failed"))
+		panic("unexpected")
+	}, Recovery(nil), Logging(logger.D), Metrics(registry))
+
+	inv := newInvocation()
+	err := invoker(inv)
+	require.ErrorContains(t, err, "panic in Read of device 'device': unexpected")
+	require.ErrorIs(t, err, contracts.NewErrorWithReason(contracts.DriverPanic, ""))
+	require.EqualError(t, inv.ReadRequests[0].Error(), "read failed")
+	require.ErrorIs(t, inv.ReadRequests[1].Error(), contracts.NewErrorWithReason(contracts.DriverPanic, ""))
+
+	// the panics are handled by the Recover of the invocation if set
+	recovered := make([]interface{}, 0)
+	inv = newInvocation()
+	inv.Recover = func(r interface{}) error {
+		recovered = append(recovered, r)
+		return contracts.NewErrorWithReason(contracts.DriverPanic, "recovered")
+	}
+	require.ErrorContains(t, invoker(inv), "recovered")
+	require.Equal(t, []interface{}{"unexpected"}, recovered)
+
+	invoker = Chain(func(inv *Invocation) error {
+		inv.ReadRequests[0].Failed(errors.New("read failed"))
+		return nil
+	}, Recovery(nil), Logging(nil), Metrics(registry))
+	require.NoError(t, invoker(newInvocation()))
+
+	require.Equal(t, int64(1), registry.Get("DriverReadLatency").(metrics.Timer).Count())
+	require.Equal(t, int64(1), registry.Get("DriverReadErrors").(metrics.Counter).Count())
+	require.NotNil(t, registry.Get("DriverReportLatency"))
+}
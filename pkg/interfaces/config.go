@@ -0,0 +1,25 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interfaces
+
+// ConfigWatcher is an optional interface implemented by driver to be notified of the changes of the Driver
+// section in the configuration provider, so that the options of driver can be changed without restart.
+type ConfigWatcher interface {
+	// OnConfigChanged passes the driver configs before and after the change. The media config has been
+	// re-applied before it is called.
+	OnConfigChanged(old, new map[string]string)
+}
@@ -48,9 +48,24 @@ type Driver interface {
 	Stop(force bool) error
 }
 
+// ContextDriver is an optional interface implemented by driver that support deadlines and cancellation, the
+// Agent prefers it to the corresponding functions of Driver if implemented. The context is done once the deadline
+// exceeds or the driver is stopping, the driver should give up the operation as soon as possible then. The requests
+// left unfinished when the deadline exceeds will be failed with the ReadTimeout, WriteTimeout or CallTimeout error.
+// The deadline is per batch of requests, that is the longest timeout of the resources requested.
+type ContextDriver interface {
+	// ReadPropertyContext is the same as ReadProperty of Driver except the given context.
+	ReadPropertyContext(ctx context.Context, device *contracts.Device, reqs []contracts.ReadRequest) error
+	// WritePropertyContext is the same as WriteProperty of Driver except the given context.
+	WritePropertyContext(ctx context.Context, device *contracts.Device, reqs []contracts.WriteRequest) error
+	// CallServiceContext is the same as CallService of Driver except the given context.
+	CallServiceContext(ctx context.Context, device *contracts.Device, reqs []contracts.CallRequest) error
+}
+
 // DeviceHandler is an optional interface to handle the system event of device
 type DeviceHandler interface {
-	// AddDevice is a callback function that is invoked when a new device associated with this driver is added
+	// AddDevice is a callback function that is invoked when a new device associated with this driver is added,
+	// the device locked when added is not passed until it is unlocked.
 	AddDevice(device *contracts.Device) error
 	// UpdateDevice is a callback function that is invoked when a device associated with this driver is updated
 	UpdateDevice(device *contracts.Device) error
@@ -58,6 +73,37 @@ type DeviceHandler interface {
 	RemoveDevice(device *contracts.Device) error
 }
 
+// AdminStateHandler is an optional interface to handle the changes of the admin state of device. The locked device
+// accepts no commands and is excluded from the status reporting and polling, the driver should release the
+// connections of the device until it is unlocked.
+type AdminStateHandler interface {
+	// OnLocked is a callback function that is invoked when a device is locked by the operator, a locked device is
+	// added, or a device is already locked when the driver is initialized.
+	OnLocked(device *contracts.Device) error
+	// OnUnlocked is a callback function that is invoked when a locked device is unlocked by the operator.
+	OnUnlocked(device *contracts.Device) error
+}
+
+// Poller is an optional interface implemented by driver that opts into the polling scheduler of SDK instead of
+// running its own tickers. The readable properties of each device are grouped by the attribute 'pollInterval', and
+// the resources of the same group are polled in one batch periodically. The polling starts and stops with the
+// device, and the results are reported asynchronously in the same way as the readings pushed by driver.
+type Poller interface {
+	// PollProperty passes a slice of ReadRequest of the device resources polled at the same interval, the results
+	// are set in the same way as ReadProperty. The context is done once the deadline exceeds or the device stops
+	// being polled.
+	PollProperty(ctx context.Context, device *contracts.Device, reqs []contracts.ReadRequest) error
+}
+
+// EventSubscriber is an optional interface implemented by driver that support device events.
+type EventSubscriber interface {
+	// SubscribeEvent passes a slice of EventRequest each representing an event of the specific device to be subscribed.
+	// Once the event occurs, the payload can be wrapped by contracts.NewEventValues and reported asynchronously.
+	SubscribeEvent(device *contracts.Device, reqs []contracts.EventRequest) error
+	// UnsubscribeEvent passes a slice of EventRequest each representing an event of the specific device to be unsubscribed.
+	UnsubscribeEvent(device *contracts.Device, reqs []contracts.EventRequest) error
+}
+
 // Discovery is an optional interface implemented by driver that support dynamic device discovery.
 type Discovery interface {
 	// Discover triggers protocol specific device discovery.
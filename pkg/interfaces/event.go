@@ -17,10 +17,15 @@
 package interfaces
 
 import (
+	"context"
+
 	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
 )
 
 // Reporter is the interface to report device events
 type Reporter interface {
+	// ReportEvent reports the event, the error is returned if the event is dropped due to the overflow policy.
 	ReportEvent(event *contracts.AsyncValues) error
+	// ReportEventContext is the same as ReportEvent except that it gives up waiting for room once ctx is done.
+	ReportEventContext(ctx context.Context, event *contracts.AsyncValues) error
 }
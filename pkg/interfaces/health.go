@@ -0,0 +1,36 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interfaces
+
+import (
+	"context"
+)
+
+// HealthChecker is an optional interface implemented by driver to report its liveness, which is checked by the
+// probe '/health/live' of the customized server. The driver is regarded as dead if the check fails or does not
+// return in time, e.g. it is deadlocked.
+type HealthChecker interface {
+	// CheckHealth returns an error if the driver is not alive and should be restarted.
+	CheckHealth(ctx context.Context) error
+}
+
+// ReadinessChecker is an optional interface implemented by driver to report its readiness, which is checked by
+// the probe '/health/ready' of the customized server together with the built-in checks.
+type ReadinessChecker interface {
+	// CheckReadiness returns an error if the driver is not ready to serve, e.g. the connection to backend is lost.
+	CheckReadiness(ctx context.Context) error
+}
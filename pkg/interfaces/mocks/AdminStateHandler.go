@@ -0,0 +1,64 @@
+// Code generated by mockery v2.40.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	contracts "github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AdminStateHandler is an autogenerated mock type for the AdminStateHandler type
+type AdminStateHandler struct {
+	mock.Mock
+}
+
+// OnLocked provides a mock function with given fields: device
+func (_m *AdminStateHandler) OnLocked(device *contracts.Device) error {
+	ret := _m.Called(device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OnLocked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*contracts.Device) error); ok {
+		r0 = rf(device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OnUnlocked provides a mock function with given fields: device
+func (_m *AdminStateHandler) OnUnlocked(device *contracts.Device) error {
+	ret := _m.Called(device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OnUnlocked")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*contracts.Device) error); ok {
+		r0 = rf(device)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAdminStateHandler creates a new instance of AdminStateHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdminStateHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdminStateHandler {
+	mock := &AdminStateHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
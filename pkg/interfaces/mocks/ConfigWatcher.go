@@ -0,0 +1,29 @@
+// Code generated by mockery v2.40.0. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// ConfigWatcher is an autogenerated mock type for the ConfigWatcher type
+type ConfigWatcher struct {
+	mock.Mock
+}
+
+// OnConfigChanged provides a mock function with given fields: old, new
+func (_m *ConfigWatcher) OnConfigChanged(old map[string]string, new map[string]string) {
+	_m.Called(old, new)
+}
+
+// NewConfigWatcher creates a new instance of ConfigWatcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewConfigWatcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ConfigWatcher {
+	mock := &ConfigWatcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
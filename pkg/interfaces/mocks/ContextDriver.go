@@ -0,0 +1,84 @@
+// Code generated by mockery v2.40.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	contracts "github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ContextDriver is an autogenerated mock type for the ContextDriver type
+type ContextDriver struct {
+	mock.Mock
+}
+
+// CallServiceContext provides a mock function with given fields: ctx, device, reqs
+func (_m *ContextDriver) CallServiceContext(ctx context.Context, device *contracts.Device, reqs []contracts.CallRequest) error {
+	ret := _m.Called(ctx, device, reqs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CallServiceContext")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *contracts.Device, []contracts.CallRequest) error); ok {
+		r0 = rf(ctx, device, reqs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadPropertyContext provides a mock function with given fields: ctx, device, reqs
+func (_m *ContextDriver) ReadPropertyContext(ctx context.Context, device *contracts.Device, reqs []contracts.ReadRequest) error {
+	ret := _m.Called(ctx, device, reqs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadPropertyContext")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *contracts.Device, []contracts.ReadRequest) error); ok {
+		r0 = rf(ctx, device, reqs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WritePropertyContext provides a mock function with given fields: ctx, device, reqs
+func (_m *ContextDriver) WritePropertyContext(ctx context.Context, device *contracts.Device, reqs []contracts.WriteRequest) error {
+	ret := _m.Called(ctx, device, reqs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WritePropertyContext")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *contracts.Device, []contracts.WriteRequest) error); ok {
+		r0 = rf(ctx, device, reqs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewContextDriver creates a new instance of ContextDriver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewContextDriver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ContextDriver {
+	mock := &ContextDriver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
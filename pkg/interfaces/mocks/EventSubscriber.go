@@ -0,0 +1,64 @@
+// Code generated by mockery v2.40.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	contracts "github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventSubscriber is an autogenerated mock type for the EventSubscriber type
+type EventSubscriber struct {
+	mock.Mock
+}
+
+// SubscribeEvent provides a mock function with given fields: device, reqs
+func (_m *EventSubscriber) SubscribeEvent(device *contracts.Device, reqs []contracts.EventRequest) error {
+	ret := _m.Called(device, reqs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*contracts.Device, []contracts.EventRequest) error); ok {
+		r0 = rf(device, reqs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnsubscribeEvent provides a mock function with given fields: device, reqs
+func (_m *EventSubscriber) UnsubscribeEvent(device *contracts.Device, reqs []contracts.EventRequest) error {
+	ret := _m.Called(device, reqs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnsubscribeEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*contracts.Device, []contracts.EventRequest) error); ok {
+		r0 = rf(device, reqs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEventSubscriber creates a new instance of EventSubscriber. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventSubscriber(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventSubscriber {
+	mock := &EventSubscriber{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,48 @@
+// Code generated by mockery v2.40.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	contracts "github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Poller is an autogenerated mock type for the Poller type
+type Poller struct {
+	mock.Mock
+}
+
+// PollProperty provides a mock function with given fields: ctx, device, reqs
+func (_m *Poller) PollProperty(ctx context.Context, device *contracts.Device, reqs []contracts.ReadRequest) error {
+	ret := _m.Called(ctx, device, reqs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PollProperty")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *contracts.Device, []contracts.ReadRequest) error); ok {
+		r0 = rf(ctx, device, reqs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPoller creates a new instance of Poller. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPoller(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Poller {
+	mock := &Poller{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	contracts "github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
 
 	mock "github.com/stretchr/testify/mock"
@@ -31,6 +33,24 @@ func (_m *Reporter) ReportEvent(event *contracts.AsyncValues) error {
 	return r0
 }
 
+// ReportEventContext provides a mock function with given fields: ctx, event
+func (_m *Reporter) ReportEventContext(ctx context.Context, event *contracts.AsyncValues) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReportEventContext")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *contracts.AsyncValues) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewReporter creates a new instance of Reporter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewReporter(t interface {
@@ -19,11 +19,13 @@ package media
 import (
 	"encoding/json"
 	"net/url"
+	"sync/atomic"
 
 	"github.com/volcengine/vei-driver-sdk-go/pkg/clients"
 )
 
-var config *Config
+// current is the media config in use, which is replaced as a whole when the config is initialized again.
+var current atomic.Value
 
 type Config struct {
 	Server   string             `json:"MediaServer"`
@@ -34,13 +36,15 @@ type Config struct {
 	Client   *clients.ZLMClient `json:"-"`
 }
 
+// InitializeConfig parses the media config from the driver configs and replaces the one in use atomically, so that
+// the streams pick up the new settings in their next operations. The config in use is kept if it fails.
 func InitializeConfig(configs map[string]string, app string) error {
 	data, err := json.Marshal(configs)
 	if err != nil {
 		return err
 	}
 
-	config = &Config{}
+	config := &Config{}
 	if err = json.Unmarshal(data, config); err != nil {
 		return err
 	}
@@ -60,33 +64,37 @@ func InitializeConfig(configs map[string]string, app string) error {
 
 	config.Client = client
 	config.App = app
+	current.Store(config)
 	return nil
 }
 
+// Media returns the media config in use, nil if it is not initialized. The returned config should be used through
+// an operation so that the settings are consistent even if the config is replaced meanwhile.
 func Media() *Config {
+	config, _ := current.Load().(*Config)
 	return config
 }
 
 func Server() string {
-	return config.Server
+	return Media().Server
 }
 
 func Secret() string {
-	return config.Secret
+	return Media().Secret
 }
 
 func VHost() string {
-	return config.VHost
+	return Media().VHost
 }
 
 func HostName() string {
-	return config.HostName
+	return Media().HostName
 }
 
 func App() string {
-	return config.App
+	return Media().App
 }
 
 func Client() *clients.ZLMClient {
-	return config.Client
+	return Media().Client
 }
@@ -31,7 +31,7 @@ const (
 )
 
 func MockMediaConfig() {
-	if config == nil {
+	if Media() == nil {
 		conf := map[string]string{
 			"MediaServer": server,
 			"MediaSecret": secret,
@@ -41,10 +41,14 @@ func MockMediaConfig() {
 	}
 }
 
+// restoreConfig stores the config back as the one in use, nil resets it to uninitialized. The config is stored
+// through the atomic value, which may be loaded concurrently.
+func restoreConfig(config *Config) {
+	current.Store(config)
+}
+
 func TestInitializeMediaConfig(t *testing.T) {
-	defer func() {
-		config = nil
-	}()
+	defer restoreConfig(Media())
 
 	type args struct {
 		configs map[string]string
@@ -78,3 +82,23 @@ func TestMedia(t *testing.T) {
 	require.Equal(t, app, App())
 	require.NotNil(t, Client())
 }
+
+func TestInitializeConfigAgain(t *testing.T) {
+	// restore the config for the other tests
+	defer restoreConfig(Media())
+
+	require.NoError(t, InitializeConfig(map[string]string{"MediaServer": server, "MediaSecret": secret}, app))
+	previous := Media()
+
+	// the config in use is kept if failed
+	require.Error(t, InitializeConfig(map[string]string{"MediaServer": "127.0.0.1:80"}, app))
+	require.Same(t, previous, Media())
+
+	// the config is replaced as a whole
+	require.NoError(t, InitializeConfig(map[string]string{"MediaServer": "http://new-media-server:8080", "MediaSecret": "new"}, app))
+	require.NotSame(t, previous, Media())
+	require.Equal(t, "new-media-server", HostName())
+	require.Equal(t, "new", Secret())
+	require.Equal(t, secret, previous.Secret)
+	require.Equal(t, "rtsp://new-media-server:554/mock/stream", URL().RTSP("stream"))
+}
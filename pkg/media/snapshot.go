@@ -41,13 +41,14 @@ func NewSnapshotResponse(snapshot []byte) *SnapshotResponse {
 }
 
 func (s *Stream) Snapshot(ctx context.Context) (*SnapshotResponse, error) {
+	config := Media()
 	expire := 1
 	timeout := 10
-	localUrl := fmt.Sprintf("rtsp://127.0.0.1:554/%s/%s", Media().App, s.name)
+	localUrl := fmt.Sprintf("rtsp://127.0.0.1:554/%s/%s", config.App, s.name)
 	stream := utils.Ternary(s.onDemand, s.url, localUrl)
 
-	resp, err := Media().Client.Native.GetSnapWithResponse(ctx, &zlm.GetSnapParams{
-		Secret:     &Media().Secret,
+	resp, err := config.Client.Native.GetSnapWithResponse(ctx, &zlm.GetSnapParams{
+		Secret:     &config.Secret,
 		Url:        &stream,
 		TimeoutSec: &timeout,
 		ExpireSec:  &expire,
@@ -134,11 +134,12 @@ func (s *Stream) Start() error {
 }
 
 func (s *Stream) start(ctx context.Context) error {
+	config := Media()
 	logger.D.Infof("add stream proxy for device %s, url=%s", s.name, s.url)
-	resp, err := Media().Client.AddStreamProxy(ctx, &zlm.AddStreamProxyParams{
-		Secret: &Media().Secret,
-		Vhost:  &Media().VHost,
-		App:    &Media().App,
+	resp, err := config.Client.AddStreamProxy(ctx, &zlm.AddStreamProxyParams{
+		Secret: &config.Secret,
+		Vhost:  &config.VHost,
+		App:    &config.App,
 		Stream: &s.name,
 		Url:    &s.url,
 	})
@@ -163,10 +164,11 @@ func (s *Stream) Stop() error {
 }
 
 func (s *Stream) stop(ctx context.Context) error {
+	config := Media()
 	logger.D.Infof("delete stream proxy for device %s", s.name)
-	key := strings.Join([]string{Media().VHost, Media().App, s.name}, "/")
-	resp, err := Media().Client.DelStreamProxy(ctx, &zlm.DelStreamProxyParams{
-		Secret: &Media().Secret,
+	key := strings.Join([]string{config.VHost, config.App, s.name}, "/")
+	resp, err := config.Client.DelStreamProxy(ctx, &zlm.DelStreamProxyParams{
+		Secret: &config.Secret,
 		Key:    &key,
 	})
 	if err != nil {
@@ -250,10 +252,11 @@ func (s *Stream) checkMediaOnline(ctx context.Context) error {
 }
 
 func (s *Stream) IsMediaOnline(ctx context.Context) (bool, error) {
-	resp, err := Media().Client.IsMediaOnline(ctx, &zlm.IsMediaOnlineParams{
-		Secret: &Media().Secret,
-		Vhost:  &Media().VHost,
-		App:    &Media().App,
+	config := Media()
+	resp, err := config.Client.IsMediaOnline(ctx, &zlm.IsMediaOnlineParams{
+		Secret: &config.Secret,
+		Vhost:  &config.VHost,
+		App:    &config.App,
 		Schema: &s.schema,
 		Stream: &s.name,
 	})
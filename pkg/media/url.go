@@ -36,25 +36,31 @@ func URL() StreamURL {
 }
 
 func (s *streamURL) RTSP(stream string) string {
-	return fmt.Sprintf("rtsp://%s:554/%s/%s", Media().HostName, Media().App, stream)
+	config := Media()
+	return fmt.Sprintf("rtsp://%s:554/%s/%s", config.HostName, config.App, stream)
 }
 
 func (s *streamURL) RTMP(stream string) string {
-	return fmt.Sprintf("rtmp://%s:1935/%s/%s", Media().HostName, Media().App, stream)
+	config := Media()
+	return fmt.Sprintf("rtmp://%s:1935/%s/%s", config.HostName, config.App, stream)
 }
 
 func (s *streamURL) HLS(stream string) string {
-	return fmt.Sprintf("http://%s/%s/%s/hls.m3u8", Media().HostName, Media().App, stream)
+	config := Media()
+	return fmt.Sprintf("http://%s/%s/%s/hls.m3u8", config.HostName, config.App, stream)
 }
 
 func (s *streamURL) FLV(stream string) string {
-	return fmt.Sprintf("http://%s/%s/%s.live.flv", Media().HostName, Media().App, stream)
+	config := Media()
+	return fmt.Sprintf("http://%s/%s/%s.live.flv", config.HostName, config.App, stream)
 }
 
 func (s *streamURL) TS(stream string) string {
-	return fmt.Sprintf("http://%s/%s/%s.live.ts", Media().HostName, Media().App, stream)
+	config := Media()
+	return fmt.Sprintf("http://%s/%s/%s.live.ts", config.HostName, config.App, stream)
 }
 
 func (s *streamURL) FMP4(stream string) string {
-	return fmt.Sprintf("http://%s/%s/%s.live.mp4", Media().HostName, Media().App, stream)
+	config := Media()
+	return fmt.Sprintf("http://%s/%s/%s.live.mp4", config.HostName, config.App, stream)
 }
@@ -19,12 +19,22 @@ package utils
 import (
 	"fmt"
 	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/edgexfoundry/device-sdk-go/v2/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
 	"github.com/spf13/cast"
 )
 
+// MaxBinarySize is the maximum size in bytes of the Binary values cast by CastCommandValue.
+var MaxBinarySize = 16 * 1024 * 1024
+
+// CastCommandValue casts the reading into the CommandValue of the value type, the numbers are checked against the
+// range of the value type. The readings of array types are slices or arrays cast element by element, and the reading
+// of Binary is a []byte, a string or a slice of bytes no larger than MaxBinarySize.
 func CastCommandValue(resourceName string, valueType string, reading interface{}) (result *models.CommandValue, err error) {
 	if !CheckValueRange(valueType, reading) {
 		err = fmt.Errorf("parse reading failed, reading %v is out of the type(%v)'s range", reading, valueType)
@@ -57,6 +67,32 @@ func CastCommandValue(resourceName string, valueType string, reading interface{}
 		val, err = cast.ToFloat32E(reading)
 	case common.ValueTypeFloat64:
 		val, err = cast.ToFloat64E(reading)
+	case common.ValueTypeBinary:
+		val, err = castBinary(reading)
+	case common.ValueTypeBoolArray:
+		val, err = castArray(valueType, reading, cast.ToBoolE)
+	case common.ValueTypeStringArray:
+		val, err = castArray(valueType, reading, cast.ToStringE)
+	case common.ValueTypeUint8Array:
+		val, err = castArray(valueType, reading, cast.ToUint8E)
+	case common.ValueTypeUint16Array:
+		val, err = castArray(valueType, reading, cast.ToUint16E)
+	case common.ValueTypeUint32Array:
+		val, err = castArray(valueType, reading, cast.ToUint32E)
+	case common.ValueTypeUint64Array:
+		val, err = castArray(valueType, reading, cast.ToUint64E)
+	case common.ValueTypeInt8Array:
+		val, err = castArray(valueType, reading, cast.ToInt8E)
+	case common.ValueTypeInt16Array:
+		val, err = castArray(valueType, reading, cast.ToInt16E)
+	case common.ValueTypeInt32Array:
+		val, err = castArray(valueType, reading, cast.ToInt32E)
+	case common.ValueTypeInt64Array:
+		val, err = castArray(valueType, reading, cast.ToInt64E)
+	case common.ValueTypeFloat32Array:
+		val, err = castArray(valueType, reading, cast.ToFloat32E)
+	case common.ValueTypeFloat64Array:
+		val, err = castArray(valueType, reading, cast.ToFloat64E)
 	case common.ValueTypeObject:
 		val = reading
 	default:
@@ -70,14 +106,57 @@ func CastCommandValue(resourceName string, valueType string, reading interface{}
 	return models.NewCommandValue(resourceName, valueType, val)
 }
 
+// castArray casts the slice or array into []T element by element, each element is checked against the range of
+// the element type of the array type.
+func castArray[T any](valueType string, reading interface{}, fn func(interface{}) (T, error)) ([]T, error) {
+	value := reflect.ValueOf(reading)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("unable to cast %#v of type %T to %s", reading, reading, valueType)
+	}
+	elemType := strings.TrimSuffix(valueType, "Array")
+	result := make([]T, value.Len())
+	for i := range result {
+		elem := value.Index(i).Interface()
+		if !CheckValueRange(elemType, elem) {
+			return nil, fmt.Errorf("element %d: %v is out of the type(%v)'s range", i, elem, elemType)
+		}
+		v, err := fn(elem)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %v", i, err)
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+// castBinary casts the []byte, string or slice of bytes into []byte no larger than MaxBinarySize.
+func castBinary(reading interface{}) (data []byte, err error) {
+	switch v := reading.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		if data, err = castArray(common.ValueTypeUint8Array, reading, cast.ToUint8E); err != nil {
+			return nil, err
+		}
+	}
+	if len(data) > MaxBinarySize {
+		return nil, fmt.Errorf("the binary of %d bytes exceeds the limit of %d bytes", len(data), MaxBinarySize)
+	}
+	return data, nil
+}
+
+// CheckValueRange returns false if the numeric reading is out of the range of the value type. The readings are
+// compared by their own kinds, so that the unsigned and float readings are not wrapped around before checked.
 func CheckValueRange(valueType string, reading interface{}) bool {
 	switch valueType {
 	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64:
-		val := cast.ToInt64(reading)
-		return checkIntValueRange(valueType, val)
+		val, ok := int64Of(reading)
+		return ok && checkIntValueRange(valueType, val)
 	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64:
-		val := cast.ToUint64(reading)
-		return checkUintValueRange(valueType, val)
+		val, ok := uint64Of(reading)
+		return ok && checkUintValueRange(valueType, val)
 	case common.ValueTypeFloat32, common.ValueTypeFloat64:
 		val := cast.ToFloat64(reading)
 		return checkFloatValueRange(valueType, val)
@@ -86,6 +165,42 @@ func CheckValueRange(valueType string, reading interface{}) bool {
 	}
 }
 
+// int64Of returns the reading as int64, ok is false if the number is out of the range of int64. The readings other
+// than numbers are cast by cast.ToInt64E, and left to the cast of the value type if they cannot be cast.
+func int64Of(reading interface{}) (val int64, ok bool) {
+	v := reflect.ValueOf(reading)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint()), v.Uint() <= math.MaxInt64
+	case reflect.Float32, reflect.Float64:
+		// The range of int64 is [-2^63, 2^63), which is false for NaN.
+		f := v.Float()
+		return int64(f), f >= math.MinInt64 && f < -math.MinInt64
+	}
+	val, _ = cast.ToInt64E(reading)
+	return val, true
+}
+
+// uint64Of returns the reading as uint64, ok is false if the number is out of the range of uint64. The readings
+// other than numbers are cast by cast.ToUint64E, and left to the cast of the value type if they cannot be cast.
+func uint64Of(reading interface{}) (val uint64, ok bool) {
+	v := reflect.ValueOf(reading)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(v.Int()), v.Int() >= 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		// The range of uint64 is [0, 2^64), which is false for NaN.
+		f := v.Float()
+		return uint64(f), f >= 0 && f < 1<<64
+	}
+	val, _ = cast.ToUint64E(reading)
+	return val, true
+}
+
 func checkIntValueRange(valueType string, val int64) bool {
 	var isValid = false
 	switch valueType {
@@ -126,3 +241,22 @@ func checkFloatValueRange(valueType string, val float64) bool {
 	}
 	return isValid
 }
+
+// CastDuration casts the value to time.Duration, the number without unit is regarded as milliseconds.
+func CastDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case string:
+		if ms, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(ms * float64(time.Millisecond)), nil
+		}
+		return time.ParseDuration(v)
+	default:
+		ms, err := cast.ToFloat64E(value)
+		if err != nil {
+			return 0, fmt.Errorf("unable to cast %#v of type %T to duration", value, value)
+		}
+		return time.Duration(ms * float64(time.Millisecond)), nil
+	}
+}
@@ -19,8 +19,10 @@ package utils
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v2/common"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCastCommandValue(t *testing.T) {
@@ -50,6 +52,23 @@ func TestCastCommandValue(t *testing.T) {
 		{name: "float32", args: args{valueType: common.ValueTypeFloat32, reading: "255"}, wantErr: false},
 		{name: "float64", args: args{valueType: common.ValueTypeFloat64, reading: "255"}, wantErr: false},
 		{name: "object", args: args{valueType: common.ValueTypeObject, reading: map[string]string{}}, wantErr: false},
+		{name: "binary", args: args{valueType: common.ValueTypeBinary, reading: []byte{0x01}}, wantErr: false},
+		{name: "binary", args: args{valueType: common.ValueTypeBinary, reading: []int{256}}, wantErr: true},
+		{name: "bool array", args: args{valueType: common.ValueTypeBoolArray, reading: []string{"true", "0"}}, wantErr: false},
+		{name: "bool array", args: args{valueType: common.ValueTypeBoolArray, reading: true}, wantErr: true},
+		{name: "string array", args: args{valueType: common.ValueTypeStringArray, reading: []interface{}{1, "2"}}, wantErr: false},
+		{name: "uint8 array", args: args{valueType: common.ValueTypeUint8Array, reading: []int{255}}, wantErr: false},
+		{name: "uint8 array", args: args{valueType: common.ValueTypeUint8Array, reading: []int{256}}, wantErr: true},
+		{name: "uint16 array", args: args{valueType: common.ValueTypeUint16Array, reading: []int{-1}}, wantErr: true},
+		{name: "uint32 array", args: args{valueType: common.ValueTypeUint32Array, reading: [2]int{1, 2}}, wantErr: false},
+		{name: "uint64 array", args: args{valueType: common.ValueTypeUint64Array, reading: []string{"255"}}, wantErr: false},
+		{name: "int8 array", args: args{valueType: common.ValueTypeInt8Array, reading: []int{128}}, wantErr: true},
+		{name: "int16 array", args: args{valueType: common.ValueTypeInt16Array, reading: []int{-32768, 32767}}, wantErr: false},
+		{name: "int16 array", args: args{valueType: common.ValueTypeInt16Array, reading: []int{32768}}, wantErr: true},
+		{name: "int32 array", args: args{valueType: common.ValueTypeInt32Array, reading: []float64{1}}, wantErr: false},
+		{name: "int64 array", args: args{valueType: common.ValueTypeInt64Array, reading: []string{"x"}}, wantErr: true},
+		{name: "float32 array", args: args{valueType: common.ValueTypeFloat32Array, reading: []float64{math.MaxFloat64}}, wantErr: true},
+		{name: "float64 array", args: args{valueType: common.ValueTypeFloat64Array, reading: []int{1}}, wantErr: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -62,6 +81,48 @@ func TestCastCommandValue(t *testing.T) {
 	}
 }
 
+func TestCastCommandValueOfArrays(t *testing.T) {
+	cv, err := CastCommandValue("registers", common.ValueTypeInt16Array, []int{-1, 2, 300})
+	require.NoError(t, err)
+	require.Equal(t, common.ValueTypeInt16Array, cv.Type)
+	require.Equal(t, []int16{-1, 2, 300}, cv.Value)
+
+	cv, err = CastCommandValue("flags", common.ValueTypeBoolArray, []int{1, 0})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, false}, cv.Value)
+
+	_, err = CastCommandValue("registers", common.ValueTypeUint8Array, []int{1, 256})
+	require.ErrorContains(t, err, "element 1")
+
+	// the elements out of the range are not wrapped around across the signs or from floats
+	_, err = CastCommandValue("registers", common.ValueTypeInt16Array, []uint64{math.MaxUint64})
+	require.ErrorContains(t, err, "element 0")
+	_, err = CastCommandValue("registers", common.ValueTypeInt64Array, []uint64{1, math.MaxInt64 + 1})
+	require.ErrorContains(t, err, "element 1")
+	_, err = CastCommandValue("registers", common.ValueTypeInt64Array, []float64{1e19})
+	require.ErrorContains(t, err, "element 0")
+	_, err = CastCommandValue("registers", common.ValueTypeUint64Array, []float64{-1})
+	require.ErrorContains(t, err, "element 0")
+	_, err = CastCommandValue("registers", common.ValueTypeUint32Array, []int64{math.MinInt64})
+	require.ErrorContains(t, err, "element 0")
+}
+
+func TestCastCommandValueOfBinary(t *testing.T) {
+	defer func(size int) { MaxBinarySize = size }(MaxBinarySize)
+	MaxBinarySize = 4
+
+	cv, err := CastCommandValue("image", common.ValueTypeBinary, "data")
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), cv.Value)
+
+	cv, err = CastCommandValue("image", common.ValueTypeBinary, []int{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, cv.Value)
+
+	_, err = CastCommandValue("image", common.ValueTypeBinary, []byte("large"))
+	require.ErrorContains(t, err, "exceeds")
+}
+
 func TestCheckValueRange(t *testing.T) {
 	type args struct {
 		valueType string
@@ -78,6 +139,16 @@ func TestCheckValueRange(t *testing.T) {
 		{name: "int32", args: args{valueType: common.ValueTypeInt32, reading: 123}, want: true},
 		{name: "uint32", args: args{valueType: common.ValueTypeUint32, reading: 123}, want: true},
 		{name: "float32", args: args{valueType: common.ValueTypeFloat32, reading: 123}, want: true},
+		{name: "int16 of max uint64", args: args{valueType: common.ValueTypeInt16, reading: uint64(math.MaxUint64)}, want: false},
+		{name: "int64 of max int64", args: args{valueType: common.ValueTypeInt64, reading: int64(math.MaxInt64)}, want: true},
+		{name: "int64 of max uint64", args: args{valueType: common.ValueTypeInt64, reading: uint64(math.MaxUint64)}, want: false},
+		{name: "int64 of 1e19", args: args{valueType: common.ValueTypeInt64, reading: 1e19}, want: false},
+		{name: "int64 of -2^63", args: args{valueType: common.ValueTypeInt64, reading: float64(math.MinInt64)}, want: true},
+		{name: "int64 of NaN", args: args{valueType: common.ValueTypeInt64, reading: math.NaN()}, want: false},
+		{name: "uint64 of max uint64", args: args{valueType: common.ValueTypeUint64, reading: uint64(math.MaxUint64)}, want: true},
+		{name: "uint64 of 2e19", args: args{valueType: common.ValueTypeUint64, reading: 2e19}, want: false},
+		{name: "uint32 of -1", args: args{valueType: common.ValueTypeUint32, reading: -1}, want: false},
+		{name: "int32 of string", args: args{valueType: common.ValueTypeInt32, reading: "2147483648"}, want: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -157,3 +228,32 @@ func Test_checkFloatValueRange(t *testing.T) {
 		})
 	}
 }
+
+func TestCastDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "duration", value: time.Second, want: time.Second},
+		{name: "duration string", value: "1.5s", want: time.Millisecond * 1500},
+		{name: "milliseconds string", value: "200", want: time.Millisecond * 200},
+		{name: "milliseconds int", value: 300, want: time.Millisecond * 300},
+		{name: "milliseconds float", value: 0.5, want: time.Microsecond * 500},
+		{name: "invalid string", value: "abc", wantErr: true},
+		{name: "invalid type", value: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CastDuration(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CastDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CastDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	// DecodeTag is the tag of struct fields naming the keys in the input of Decode.
+	DecodeTag = "json"
+	// DefaultTag is the tag of struct fields specifying the values used if the keys are absent in the input.
+	DefaultTag = "default"
+)
+
+// Decode decodes the input, such as the attributes of resources or the properties of protocols, into the target
+// which must be a non-nil pointer to struct. The fields absent in the input are set to their 'default' tags, the
+// values are converted weakly, e.g. "0x10" to 16 and "1,2" to []string{"1", "2"}, and the numbers decoded into
+// time.Duration are regarded as milliseconds. The target is validated by Validate after decoded.
+func Decode(input interface{}, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode target must be a non-nil pointer to struct, got %T", target)
+	}
+	if err := setDefaults(value.Elem()); err != nil {
+		return err
+	}
+	if err := decode(input, target); err != nil {
+		return err
+	}
+	return Validate(target)
+}
+
+// decode decodes the input into the target weakly.
+func decode(input interface{}, target interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.ComposeDecodeHookFunc(decodeDuration, mapstructure.StringToSliceHookFunc(",")),
+		WeaklyTypedInput: true,
+		TagName:          DecodeTag,
+		Result:           target,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(input)
+}
+
+// setDefaults sets the zero fields of the struct to their 'default' tags, the nested structs are set recursively.
+func setDefaults(value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field, structField := value.Field(i), value.Type().Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		tag, ok := structField.Tag.Lookup(DefaultTag)
+		if !ok {
+			if field.Kind() == reflect.Struct {
+				if err := setDefaults(field); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !field.IsZero() {
+			continue
+		}
+		if err := decode(tag, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid default value of field '%s': %v", structField.Name, err)
+		}
+	}
+	return nil
+}
+
+// decodeDuration decodes the data into time.Duration by CastDuration.
+func decodeDuration(_ reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+	return CastDuration(data)
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2023 Beijing Volcano Engine Technology Ltd.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRegister struct {
+	Address   uint16        `json:"address" validate:"max=9999"`
+	Length    int           `json:"length" default:"1" validate:"min=1"`
+	ByteOrder string        `json:"byteOrder" default:"ABCD" validate:"oneof=ABCD DCBA BADC CDAB"`
+	Interval  time.Duration `json:"interval" default:"1s"`
+	Tags      []string      `json:"tags"`
+	Options   struct {
+		Retries int `json:"retries" default:"3"`
+	} `json:"options"`
+}
+
+func TestDecode(t *testing.T) {
+	var register testRegister
+	err := Decode(map[string]interface{}{
+		"address":  "0x10",
+		"interval": 500,
+		"tags":     "a,b",
+		"unused":   true,
+	}, &register)
+	require.NoError(t, err)
+	require.Equal(t, uint16(16), register.Address)
+	require.Equal(t, 1, register.Length)
+	require.Equal(t, "ABCD", register.ByteOrder)
+	require.Equal(t, 500*time.Millisecond, register.Interval)
+	require.Equal(t, []string{"a", "b"}, register.Tags)
+	require.Equal(t, 3, register.Options.Retries)
+
+	register = testRegister{}
+	err = Decode(map[string]interface{}{
+		"length":    "4",
+		"byteOrder": "DCBA",
+		"interval":  "2m",
+		"options":   map[string]interface{}{"retries": 0},
+	}, &register)
+	require.NoError(t, err)
+	require.Equal(t, 4, register.Length)
+	require.Equal(t, "DCBA", register.ByteOrder)
+	require.Equal(t, 2*time.Minute, register.Interval)
+	require.Equal(t, 0, register.Options.Retries)
+}
+
+func TestDecodeFailed(t *testing.T) {
+	var register testRegister
+	require.Error(t, Decode(map[string]interface{}{"address": "abc"}, &register))
+	require.Error(t, Decode(map[string]interface{}{"address": 10000}, &register))
+	require.Error(t, Decode(map[string]interface{}{"byteOrder": "ABC"}, &testRegister{}))
+	require.Error(t, Decode(map[string]interface{}{"interval": "1 day"}, &testRegister{}))
+	require.Error(t, Decode(map[string]interface{}{}, register))
+	require.Error(t, Decode(map[string]interface{}{}, (*testRegister)(nil)))
+
+	var invalid struct {
+		Length int `json:"length" default:"one"`
+	}
+	require.Error(t, Decode(map[string]interface{}{}, &invalid))
+}
@@ -17,8 +17,16 @@
 package vei
 
 import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/volcengine/vei-driver-sdk-go/internal/middleware"
 	"github.com/volcengine/vei-driver-sdk-go/internal/runtime"
+	"github.com/volcengine/vei-driver-sdk-go/internal/spool"
 	"github.com/volcengine/vei-driver-sdk-go/internal/status"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/contracts"
+	"github.com/volcengine/vei-driver-sdk-go/pkg/interceptor"
 	"github.com/volcengine/vei-driver-sdk-go/pkg/interfaces"
 )
 
@@ -49,3 +57,174 @@ func WithStrictMode(strict bool) runtime.Option {
 		agent.StrictMode = strict
 	}
 }
+
+func WithCommandTimeout(timeout time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.CommandTimeout = timeout
+	}
+}
+
+func WithMaxConcurrency(concurrency int) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.MaxConcurrency = concurrency
+	}
+}
+
+func WithQueueTimeout(timeout time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.QueueTimeout = timeout
+	}
+}
+
+func WithRetryPolicy(policy contracts.RetryPolicy) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.RetryPolicy = policy
+	}
+}
+
+func WithReadCacheTTL(ttl time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.ReadCacheTTL = ttl
+	}
+}
+
+func WithInterceptors(interceptors ...interceptor.Interceptor) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.Interceptors = append(agent.Interceptors, interceptors...)
+	}
+}
+
+func WithWriteValidation(validate bool) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.SkipWriteValidation = !validate
+	}
+}
+
+func WithHTTPAddress(address string) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.HTTPAddress = address
+	}
+}
+
+func WithHTTPShutdownTimeout(timeout time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.HTTPShutdownTimeout = timeout
+	}
+}
+
+// WithRoute registers the route of driver to the customized server, the built-in routes take precedence.
+func WithRoute(route string, handler func(http.ResponseWriter, *http.Request), methods ...string) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.Routes = append(agent.Routes, runtime.NewRoute(route, handler, methods...))
+	}
+}
+
+// WithHTTPTLS enables TLS of the customized server with the files of certificate and key in PEM format.
+func WithHTTPTLS(certFile string, keyFile string) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.HTTPSecurity.TLSCertFile = certFile
+		agent.HTTPSecurity.TLSKeyFile = keyFile
+	}
+}
+
+// WithBearerAuth requires the bearer token for the requests to the customized server except the hooks of media
+// server, which are verified by the media secret instead.
+func WithBearerAuth(token string) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.HTTPSecurity.AuthMode = middleware.AuthBearer
+		agent.HTTPSecurity.AuthToken = token
+	}
+}
+
+// WithHMACAuth requires the requests to the customized server signed by the key except the hooks of media
+// server, which are verified by the media secret instead.
+func WithHMACAuth(key string) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.HTTPSecurity.AuthMode = middleware.AuthHMAC
+		agent.HTTPSecurity.AuthToken = key
+	}
+}
+
+// WithOverflowPolicy specifies the policy to handle the async values reported when the buffer is full, the
+// timeout is only used by the Block policy.
+func WithOverflowPolicy(policy contracts.OverflowPolicy, timeout time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.OverflowPolicy = policy
+		agent.OverflowTimeout = timeout
+	}
+}
+
+// WithSpool enables the durable spool of async values in the directory, the values are spooled during the
+// outages of delivery and replayed in order once it recovers. The oldest values are dropped when the size of
+// spool exceeds maxSize in bytes or they are older than maxAge.
+func WithSpool(dir string, maxSize int64, maxAge time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.SpoolOptions = spool.Options{Dir: dir, MaxSize: maxSize, MaxAge: maxAge}
+	}
+}
+
+func WithDeliveryTimeout(timeout time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.DeliveryTimeout = timeout
+	}
+}
+
+// WithDeliveryCheck specifies the check of the backend receiving the events, the async values are spooled while it
+// fails. By default, the message bus or core-data configured by the env overrides of EdgeX is dialed.
+func WithDeliveryCheck(check func(ctx context.Context) error) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.DeliveryCheck = check
+	}
+}
+
+// WithPollJitter specifies the ratio of the random jitter to the interval of polling, which is only used if the
+// driver implements Poller.
+func WithPollJitter(jitter float64) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.PollJitter = jitter
+	}
+}
+
+// WithAutoEventFilter filters the reads of the resources polled by the AutoEvents of devices, according to the
+// attributes 'reportOnChange', 'deadband', 'deadbandPercent' and 'maxSilence' of resources. The device service
+// cannot tell the AutoEvents from the reads by core-command, so the latter are filtered for these resources too.
+func WithAutoEventFilter() runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.FilterAutoEvents = true
+	}
+}
+
+// WithTransformProperties applies the transform properties of resources in the SDK, so that the raw values read by
+// driver can be cast to the value types of resources after transformed. The DataTransform of the device service
+// must be disabled, otherwise the properties are applied twice.
+func WithTransformProperties() runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.TransformProperties = true
+	}
+}
+
+// WithTransformAttributes applies the attributes 'mask', 'shift', 'base', 'scale' and 'offset' of resources in the
+// SDK, so that the drivers do not transform the raw values by themselves. The resources whose attribute 'transform'
+// is false are not transformed by the SDK at all.
+func WithTransformAttributes() runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.TransformAttributes = true
+	}
+}
+
+// WithSaturationPeriod specifies how long the async queue is full before the readiness probe fails, 30s by default.
+// The readiness probe also fails for the period after the async values are lost, i.e. timed out by the Block policy
+// or rejected by the DropNewest policy.
+func WithSaturationPeriod(period time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.SaturationPeriod = period
+	}
+}
+
+// WithStallTimeout specifies how long a command or the forwarding of async values makes no progress before the
+// liveness probe fails, 5m by default.
+func WithStallTimeout(timeout time.Duration) runtime.Option {
+	return func(agent *runtime.Agent) {
+		agent.StallTimeout = timeout
+	}
+}